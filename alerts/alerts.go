@@ -0,0 +1,146 @@
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"polyterm/query"
+	"polyterm/types"
+)
+
+const (
+	MetricYes       = "yes"
+	MetricChange24h = "change24h"
+	MetricVolume24h = "volume24h"
+	MetricLiquidity = "liquidity"
+
+	OpAbove = "above"
+	OpBelow = "below"
+)
+
+type Rule struct {
+	MarketID  string  `json:"marketId"`
+	Label     string  `json:"label,omitempty"`
+	Metric    string  `json:"metric"`
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+}
+
+type Event struct {
+	Rule   Rule
+	Market types.Market
+	Value  float64
+	At     time.Time
+}
+
+type Engine struct {
+	rules []Rule
+	armed map[int]bool
+}
+
+func NewEngine(rules []Rule) *Engine {
+	e := &Engine{}
+	e.SetRules(rules)
+	return e
+}
+
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+func (e *Engine) SetRules(rules []Rule) {
+	e.rules = rules
+	e.armed = make(map[int]bool, len(rules))
+	for i := range rules {
+		e.armed[i] = true
+	}
+}
+
+func (e *Engine) Add(rule Rule) {
+	e.rules = append(e.rules, rule)
+	e.armed[len(e.rules)-1] = true
+}
+
+func (e *Engine) Evaluate(markets []types.Market) []Event {
+	byID := make(map[string]*types.Market, len(markets))
+	for i := range markets {
+		byID[markets[i].ID] = &markets[i]
+	}
+
+	var events []Event
+	now := time.Now()
+	for i, rule := range e.rules {
+		market, ok := byID[rule.MarketID]
+		if !ok {
+			continue
+		}
+		value := MetricValue(market, rule.Metric)
+		if !rule.Matches(value) {
+			e.armed[i] = true
+			continue
+		}
+		if e.armed[i] {
+			events = append(events, Event{Rule: rule, Market: *market, Value: value, At: now})
+			e.armed[i] = false
+		}
+	}
+	return events
+}
+
+func (r Rule) Matches(value float64) bool {
+	switch r.Op {
+	case OpAbove:
+		return value > r.Threshold
+	case OpBelow:
+		return value < r.Threshold
+	}
+	return false
+}
+
+func (r Rule) String() string {
+	name := r.Label
+	if name == "" {
+		name = r.MarketID
+	}
+	return fmt.Sprintf("%s %s %s %g", name, r.Metric, r.Op, r.Threshold)
+}
+
+func MetricValue(m *types.Market, metric string) float64 {
+	if mt, ok := query.Lookup(metric); ok {
+		return mt.Value(m)
+	}
+	return 0
+}
+
+func ParseCondition(s string) (metric, op string, threshold float64, err error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 2 {
+		fields = append([]string{MetricYes}, fields...)
+	}
+	if len(fields) != 3 {
+		return "", "", 0, fmt.Errorf("expected [metric] above|below <value>, got %q", s)
+	}
+
+	metric, op = fields[0], fields[1]
+	mt, ok := query.Lookup(metric)
+	if !ok {
+		return "", "", 0, fmt.Errorf("unknown metric %q", metric)
+	}
+	metric = mt.Name
+	switch op {
+	case OpAbove, ">":
+		op = OpAbove
+	case OpBelow, "<":
+		op = OpBelow
+	default:
+		return "", "", 0, fmt.Errorf("unknown comparison %q", op)
+	}
+
+	threshold, err = strconv.ParseFloat(strings.TrimSuffix(fields[2], "%"), 64)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid threshold %q", fields[2])
+	}
+	return metric, op, threshold, nil
+}
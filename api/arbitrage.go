@@ -0,0 +1,309 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	"polyterm/types"
+)
+
+const (
+	ArbBuyAll  = "buy all"
+	ArbSellAll = "sell all"
+
+	ArbKindEvent = "event"
+	ArbKindPair  = "yes/no"
+
+	arbEventLimit  = 200
+	arbDepthGroups = 20
+	arbPairGroups  = 20
+	arbBookWorkers = 8
+	pairDrift      = 0.005
+)
+
+func FetchEvents(ctx context.Context, limit int) ([]types.Event, error) {
+	var events []types.Event
+	url := fmt.Sprintf("%s/events?closed=false&active=true&limit=%d&order=volume24hr&ascending=false", BaseURL, limit)
+	if err := fetchWithTimeout(ctx, url, &events); err != nil {
+		return nil, fmt.Errorf("events: %w", err)
+	}
+	return events, nil
+}
+
+func ScanArbitrage(ctx context.Context, markets []types.Market, feeBps float64) ([]types.ArbGroup, error) {
+	events, err := FetchEvents(ctx, arbEventLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate := feeBps / 10000
+	groups := ArbitrageGroups(events, markets, feeRate)
+
+	ctx, cancel := context.WithTimeout(ctx, 2*Timeout)
+	defer cancel()
+	err = fillDepth(ctx, groups, feeRate)
+	priced := groups[:0]
+	for _, g := range groups {
+		if g.Kind != ArbKindPair || g.Depth {
+			priced = append(priced, g)
+		}
+	}
+	sortArbitrage(priced)
+	return priced, err
+}
+
+func ArbitrageGroups(events []types.Event, markets []types.Market, feeRate float64) []types.ArbGroup {
+	var groups []types.ArbGroup
+
+	for i := range events {
+		e := &events[i]
+		if !e.NegRisk {
+			continue
+		}
+		g := types.ArbGroup{Key: "event:" + e.ID, Title: e.Title, Kind: ArbKindEvent}
+		complete := !e.NegRiskAugmented
+		for j := range e.Markets {
+			m := &e.Markets[j]
+			if !m.Active || m.Closed {
+				if winner, ok := Winner(m); !ok || winner != 1 {
+					complete = false
+					break
+				}
+				continue
+			}
+			var token string
+			if ids := m.GetClobTokenIDs(); len(ids) > 0 {
+				token = ids[0]
+			}
+			g.Legs = append(g.Legs, types.ArbLeg{
+				MarketID: m.ID,
+				Question: m.Question,
+				Outcome:  "Yes",
+				TokenID:  token,
+				Ask:      quoteOr(m.BestAsk, 1),
+				Bid:      m.BestBid,
+			})
+		}
+		if !complete || len(g.Legs) < 2 {
+			continue
+		}
+		priceGroup(&g, feeRate)
+		groups = append(groups, g)
+	}
+
+	for i := range markets {
+		m := &markets[i]
+		ids := m.GetClobTokenIDs()
+		if len(ids) != 2 || m.BestAsk <= 0 || !pairCandidate(m) {
+			continue
+		}
+		outcomes := m.GetOutcomes()
+		if len(outcomes) != 2 {
+			outcomes = []string{"Yes", "No"}
+		}
+		g := types.ArbGroup{
+			Key:   "market:" + m.ID,
+			Title: m.Question,
+			Kind:  ArbKindPair,
+			Legs: []types.ArbLeg{
+				{MarketID: m.ID, Question: m.Question, Outcome: outcomes[0], TokenID: ids[0], Ask: m.BestAsk, Bid: m.BestBid},
+				{MarketID: m.ID, Question: m.Question, Outcome: outcomes[1], TokenID: ids[1], Ask: 1},
+			},
+		}
+		priceGroup(&g, feeRate)
+		groups = append(groups, g)
+	}
+
+	sortArbitrage(groups)
+	return groups
+}
+
+func pairCandidate(m *types.Market) bool {
+	if m.BestBid > m.BestAsk {
+		return true
+	}
+	sum := 0.0
+	for _, p := range m.GetOutcomePrices() {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return false
+		}
+		sum += v
+	}
+	return math.Abs(sum-1) > pairDrift
+}
+
+func quoteOr(price, fallback float64) float64 {
+	if price <= 0 || price >= 1 {
+		return fallback
+	}
+	return price
+}
+
+func fee(price, rate float64) float64 {
+	return rate * math.Min(price, 1-price)
+}
+
+func priceGroup(g *types.ArbGroup, feeRate float64) {
+	var buyFees, sellFees float64
+	g.SumAsk, g.SumBid = 0, 0
+	for _, leg := range g.Legs {
+		g.SumAsk += leg.Ask
+		g.SumBid += leg.Bid
+		buyFees += fee(leg.Ask, feeRate)
+		sellFees += fee(leg.Bid, feeRate)
+	}
+	g.BuyEdge = 1 - g.SumAsk - buyFees
+	g.SellEdge = g.SumBid - sellFees - 1
+
+	g.Side, g.Edge, g.Fees = ArbBuyAll, g.BuyEdge, buyFees
+	if g.SellEdge > g.BuyEdge {
+		g.Side, g.Edge, g.Fees = ArbSellAll, g.SellEdge, sellFees
+	}
+}
+
+func sortArbitrage(groups []types.ArbGroup) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Edge > groups[j].Edge
+	})
+}
+
+func fillDepth(ctx context.Context, groups []types.ArbGroup, feeRate float64) error {
+	tokens := make(map[string]bool)
+	var candidates []int
+	events, pairs := 0, 0
+	for i := range groups {
+		pair := groups[i].Kind == ArbKindPair
+		if pair && pairs == arbPairGroups || !pair && (groups[i].Edge <= 0 || events == arbDepthGroups) {
+			continue
+		}
+		complete := true
+		for _, leg := range groups[i].Legs {
+			complete = complete && leg.TokenID != ""
+		}
+		if !complete {
+			continue
+		}
+		if pair {
+			pairs++
+		} else {
+			events++
+		}
+		candidates = append(candidates, i)
+		for _, leg := range groups[i].Legs {
+			tokens[leg.TokenID] = true
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		books = make(map[string]types.OrderBook, len(tokens))
+		errs  []error
+		sem   = make(chan struct{}, arbBookWorkers)
+	)
+	for token := range tokens {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			book, err := FetchBook(ctx, token)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			books[token] = book
+		}(token)
+	}
+	wg.Wait()
+
+	for _, i := range candidates {
+		g := &groups[i]
+		asks := make([][]types.BookLevel, len(g.Legs))
+		bids := make([][]types.BookLevel, len(g.Legs))
+		complete := true
+		for j := range g.Legs {
+			book, ok := books[g.Legs[j].TokenID]
+			if !ok {
+				complete = false
+				break
+			}
+			asks[j], bids[j] = book.Asks, book.Bids
+
+			leg := &g.Legs[j]
+			leg.Ask, leg.AskSize = 1, 0
+			if len(book.Asks) > 0 {
+				leg.Ask, leg.AskSize = book.Asks[0].Price, book.Asks[0].Size
+			}
+			leg.Bid, leg.BidSize = 0, 0
+			if len(book.Bids) > 0 {
+				leg.Bid, leg.BidSize = book.Bids[0].Price, book.Bids[0].Size
+			}
+		}
+		if !complete {
+			continue
+		}
+
+		priceGroup(g, feeRate)
+		g.Depth = true
+		if g.Side == ArbBuyAll {
+			g.MaxSize = depthSize(asks, feeRate, true)
+		} else {
+			g.MaxSize = depthSize(bids, feeRate, false)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func depthSize(levels [][]types.BookLevel, feeRate float64, buy bool) float64 {
+	idx := make([]int, len(levels))
+	remaining := make([]float64, len(levels))
+	for i, side := range levels {
+		if len(side) == 0 {
+			return 0
+		}
+		remaining[i] = side[0].Size
+	}
+
+	size := 0.0
+	for {
+		total, step := 0.0, math.Inf(1)
+		for i, side := range levels {
+			p := side[idx[i]].Price
+			if buy {
+				total += p + fee(p, feeRate)
+			} else {
+				total += p - fee(p, feeRate)
+			}
+			step = math.Min(step, remaining[i])
+		}
+		if (buy && total >= 1) || (!buy && total <= 1) {
+			return size
+		}
+
+		size += step
+		for i, side := range levels {
+			remaining[i] -= step
+			if remaining[i] > 1e-9 {
+				continue
+			}
+			idx[i]++
+			if idx[i] == len(side) {
+				return size
+			}
+			remaining[i] = side[idx[i]].Size
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"math"
+	"testing"
+
+	"polyterm/types"
+)
+
+func TestDepthSize(t *testing.T) {
+	levels := func(pairs ...float64) []types.BookLevel {
+		out := make([]types.BookLevel, 0, len(pairs)/2)
+		for i := 0; i+1 < len(pairs); i += 2 {
+			out = append(out, types.BookLevel{Price: pairs[i], Size: pairs[i+1]})
+		}
+		return out
+	}
+	asks := [][]types.BookLevel{
+		levels(0.40, 100, 0.45, 50),
+		levels(0.50, 60, 0.58, 200),
+	}
+	bids := [][]types.BookLevel{
+		levels(0.55, 30, 0.50, 100),
+		levels(0.52, 80, 0.40, 10),
+	}
+
+	tests := []struct {
+		name    string
+		levels  [][]types.BookLevel
+		feeRate float64
+		buy     bool
+		want    float64
+	}{
+		{"buy without fees", asks, 0, true, 100},
+		{"buy below fee threshold", asks, 0.02, true, 100},
+		{"buy above fee threshold", asks, 0.05, true, 60},
+		{"buy with no edge", [][]types.BookLevel{levels(0.6, 10), levels(0.5, 10)}, 0, true, 0},
+		{"buy exhausts book", [][]types.BookLevel{levels(0.3, 10), levels(0.3, 25)}, 0, true, 10},
+		{"buy with empty leg", [][]types.BookLevel{levels(0.3, 10), nil}, 0, true, 0},
+		{"sell without fees", bids, 0, false, 80},
+		{"sell above fee threshold", bids, 0.05, false, 30},
+		{"sell with fees eating the edge", bids, 0.1, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := depthSize(tt.levels, tt.feeRate, tt.buy); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("depthSize = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFee(t *testing.T) {
+	tests := []struct {
+		price, rate, want float64
+	}{
+		{0.5, 0.02, 0.01},
+		{0.9, 0.02, 0.002},
+		{0.1, 0.02, 0.002},
+		{0.5, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := fee(tt.price, tt.rate); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("fee(%v, %v) = %v, want %v", tt.price, tt.rate, got, tt.want)
+		}
+	}
+}
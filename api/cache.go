@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"polyterm/types"
+)
+
+type CacheObserver interface {
+	ObserveFetch(d time.Duration, markets []types.Market, err error)
+	ObserveCacheHit()
+}
+
+type Cache struct {
+	ttl      time.Duration
+	observer CacheObserver
+
+	mu       sync.Mutex
+	limit    int
+	markets  []types.Market
+	stats    types.GlobalStats
+	at       time.Time
+	inflight chan struct{}
+	err      error
+}
+
+func NewCache(ttl time.Duration, observer CacheObserver) *Cache {
+	return &Cache{ttl: ttl, observer: observer}
+}
+
+// Markets returns the cached listing for limit, sharing one upstream fetch
+// between concurrent callers. The fetch is detached from ctx, which only
+// bounds how long this caller waits for it.
+func (c *Cache) Markets(ctx context.Context, limit int) ([]types.Market, types.GlobalStats, error) {
+	c.mu.Lock()
+	for {
+		if c.limit == limit && !c.at.IsZero() && time.Since(c.at) < c.ttl {
+			markets, stats := c.markets, c.stats
+			c.mu.Unlock()
+			c.hit()
+			return markets, stats, nil
+		}
+		fetched := c.inflight == nil
+		if fetched {
+			c.inflight = make(chan struct{})
+			go c.fetch(context.WithoutCancel(ctx), limit, c.inflight)
+		}
+		wait := c.inflight
+		c.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, types.GlobalStats{}, ctx.Err()
+		}
+		c.mu.Lock()
+		if c.limit == limit && c.err != nil {
+			err := c.err
+			c.mu.Unlock()
+			return nil, types.GlobalStats{}, err
+		}
+		if c.limit == limit && !c.at.IsZero() {
+			markets, stats := c.markets, c.stats
+			c.mu.Unlock()
+			if !fetched {
+				c.hit()
+			}
+			return markets, stats, nil
+		}
+	}
+}
+
+// fetch runs the shared upstream request and publishes its result to every
+// caller waiting on done.
+func (c *Cache) fetch(ctx context.Context, limit int, done chan struct{}) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	start := time.Now()
+	markets, stats, err := FetchMarkets(ctx, limit)
+	if c.observer != nil {
+		c.observer.ObserveFetch(time.Since(start), markets, err)
+	}
+
+	c.mu.Lock()
+	if limit != c.limit {
+		c.markets, c.stats, c.at = nil, types.GlobalStats{}, time.Time{}
+	}
+	c.limit, c.err = limit, err
+	if err == nil {
+		c.markets, c.stats, c.at = markets, stats, time.Now()
+	}
+	c.inflight = nil
+	close(done)
+	c.mu.Unlock()
+}
+
+func (c *Cache) hit() {
+	if c.observer != nil {
+		c.observer.ObserveCacheHit()
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"math"
+	"sort"
+
+	"polyterm/types"
+)
+
+func CategoryStats(markets []types.Market) []types.CategoryStats {
+	byName := make(map[string]*types.CategoryStats)
+	spreads := make(map[string][]float64)
+	var order []string
+
+	for i := range markets {
+		m := &markets[i]
+		name := m.GetCategory()
+		c, ok := byName[name]
+		if !ok {
+			c = &types.CategoryStats{Category: name}
+			byName[name] = c
+			order = append(order, name)
+		}
+
+		c.Markets++
+		c.Volume24h += m.Volume24hr
+		c.TotalVolume += m.GetVolume()
+		c.Liquidity += m.GetLiquidity()
+		c.OpenInterest += m.OpenInterest
+		c.AvgAbsChange24h += math.Abs(m.OneDayPriceChange)
+		if spread := m.GetSpread(); spread > 0 {
+			spreads[name] = append(spreads[name], spread)
+		}
+	}
+
+	stats := make([]types.CategoryStats, 0, len(order))
+	for _, name := range order {
+		c := byName[name]
+		c.AvgAbsChange24h /= float64(c.Markets)
+		c.MedianSpread = median(spreads[name])
+		stats = append(stats, *c)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Volume24h > stats[j].Volume24h
+	})
+	return stats
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"polyterm/types"
+)
+
+const ClobURL = "https://clob.polymarket.com"
+
+type bookLevel struct {
+	Price string `json:"price"`
+	Size  string `json:"size"`
+}
+
+type bookResponse struct {
+	Bids []bookLevel `json:"bids"`
+	Asks []bookLevel `json:"asks"`
+}
+
+func getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "polyterm/1.0.0")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, truncateString(string(body), 200))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
+
+func FetchBook(ctx context.Context, tokenID string) (types.OrderBook, error) {
+	var raw bookResponse
+	if err := getJSON(ctx, ClobURL+"/book?token_id="+url.QueryEscape(tokenID), &raw); err != nil {
+		return types.OrderBook{}, fmt.Errorf("order book %s: %w", tokenID, err)
+	}
+
+	book := types.OrderBook{Bids: parseLevels(raw.Bids), Asks: parseLevels(raw.Asks)}
+	sort.Slice(book.Bids, func(i, j int) bool { return book.Bids[i].Price > book.Bids[j].Price })
+	sort.Slice(book.Asks, func(i, j int) bool { return book.Asks[i].Price < book.Asks[j].Price })
+	return book, nil
+}
+
+func parseLevels(raw []bookLevel) []types.BookLevel {
+	levels := make([]types.BookLevel, 0, len(raw))
+	for _, l := range raw {
+		price, err1 := strconv.ParseFloat(l.Price, 64)
+		size, err2 := strconv.ParseFloat(l.Size, 64)
+		if err1 != nil || err2 != nil || size <= 0 {
+			continue
+		}
+		levels = append(levels, types.BookLevel{Price: price, Size: size})
+	}
+	return levels
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"polyterm/types"
+)
+
+const SiteURL = "https://polymarket.com"
+
+type MarketRef struct {
+	ID     string
+	Event  string
+	Market string
+}
+
+func MarketURL(m *types.Market) string {
+	event, market := m.EventSlug(), m.GetSlug()
+	switch {
+	case event == "" && market == "":
+		return SiteURL
+	case event == "":
+		return SiteURL + "/event/" + url.PathEscape(market)
+	case market == "" || market == event:
+		return SiteURL + "/event/" + url.PathEscape(event)
+	}
+	return SiteURL + "/event/" + url.PathEscape(event) + "/" + url.PathEscape(market)
+}
+
+func ParseMarketRef(s string) (MarketRef, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return MarketRef{}, errors.New("empty market reference")
+	}
+	if !strings.Contains(s, "/") {
+		if strings.Trim(s, "0123456789") == "" {
+			return MarketRef{ID: s}, nil
+		}
+		return MarketRef{Event: s, Market: s}, nil
+	}
+
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return MarketRef{}, fmt.Errorf("invalid market URL: %w", err)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host != "polymarket.com" {
+		return MarketRef{}, fmt.Errorf("not a Polymarket URL: %s", u.Host)
+	}
+
+	var parts []string
+	for _, p := range strings.Split(u.Path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	switch {
+	case len(parts) == 2 && parts[0] == "event":
+		return MarketRef{Event: parts[1]}, nil
+	case len(parts) >= 3 && parts[0] == "event":
+		return MarketRef{Event: parts[1], Market: parts[2]}, nil
+	case len(parts) == 2 && parts[0] == "market":
+		return MarketRef{Market: parts[1]}, nil
+	}
+	return MarketRef{}, fmt.Errorf("unrecognized Polymarket URL %q (expected /event/<slug> or /market/<slug>)", u.Path)
+}
+
+func (r MarketRef) String() string {
+	switch {
+	case r.ID != "":
+		return r.ID
+	case r.Market != "" && r.Market != r.Event:
+		return r.Market
+	}
+	return r.Event
+}
+
+func (r MarketRef) MatchMarket(m *types.Market) bool {
+	if r.ID != "" {
+		return m.ID == r.ID
+	}
+	return r.Market != "" && (m.Slug == r.Market || m.MarketSlug == r.Market)
+}
+
+func (r MarketRef) MatchEvent(m *types.Market) bool {
+	return r.Event != "" && m.EventSlug() == r.Event
+}
+
+func FindMarket(markets []types.Market, r MarketRef) int {
+	for i := range markets {
+		if r.MatchMarket(&markets[i]) {
+			return i
+		}
+	}
+	best := -1
+	for i := range markets {
+		if r.MatchEvent(&markets[i]) && (best < 0 || markets[i].Volume24hr > markets[best].Volume24hr) {
+			best = i
+		}
+	}
+	return best
+}
+
+func FetchMarketRef(ctx context.Context, r MarketRef) ([]types.Market, error) {
+	if r.ID != "" {
+		return FetchMarketsByID(ctx, []string{r.ID})
+	}
+	if r.Market != "" {
+		var markets []types.Market
+		if err := fetchWithTimeout(ctx, BaseURL+"/markets?slug="+url.QueryEscape(r.Market), &markets); err != nil {
+			return nil, fmt.Errorf("market %s: %w", r.Market, err)
+		}
+		if len(markets) > 0 || r.Event == "" {
+			return markets, nil
+		}
+	}
+
+	var events []types.Event
+	if err := fetchWithTimeout(ctx, BaseURL+"/events?slug="+url.QueryEscape(r.Event), &events); err != nil {
+		return nil, fmt.Errorf("event %s: %w", r.Event, err)
+	}
+	var markets []types.Market
+	for _, e := range events {
+		for _, m := range e.Markets {
+			m.Events = []types.Event{{ID: e.ID, Slug: e.Slug, Title: e.Title, NegRisk: e.NegRisk}}
+			markets = append(markets, m)
+		}
+	}
+	return markets, nil
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"polyterm/search"
+	"polyterm/types"
+)
+
+type SortMode int
+
+const (
+	SortVolume SortMode = iota
+	SortChange
+	SortLiquidity
+	SortVolume24h
+	SortOdds
+)
+
+var SortNames = []string{"Volume", "Change", "Liquidity", "24h Volume", "Odds"}
+
+type FilterMode int
+
+const (
+	FilterAll FilterMode = iota
+	FilterCrypto
+	FilterPolitics
+	FilterSports
+	FilterEntertainment
+)
+
+var FilterNames = []string{"All", "Crypto", "Politics", "Sports", "Entertainment"}
+
+func Listing(markets []types.Market, index *search.Index, query string, filter FilterMode, by SortMode) []types.Market {
+	filtered := make([]types.Market, 0)
+	if query != "" && index != nil {
+		for _, result := range index.Search(query) {
+			if MatchesFilter(&markets[result.Index], filter) {
+				filtered = append(filtered, markets[result.Index])
+			}
+		}
+		return filtered
+	}
+	for i := range markets {
+		if MatchesFilter(&markets[i], filter) {
+			filtered = append(filtered, markets[i])
+		}
+	}
+	SortMarkets(filtered, by)
+	return filtered
+}
+
+func MatchesFilter(market *types.Market, filter FilterMode) bool {
+	vol := market.GetVolume()
+	if vol < 100 && market.Volume24hr < 10 {
+		return false
+	}
+
+	if filter == FilterAll {
+		return true
+	}
+
+	category := strings.ToLower(market.Category)
+	question := strings.ToLower(market.Question)
+
+	switch filter {
+	case FilterCrypto:
+		return strings.Contains(category, "crypto") ||
+			strings.Contains(question, "bitcoin") ||
+			strings.Contains(question, "ethereum") ||
+			strings.Contains(question, "crypto")
+	case FilterPolitics:
+		return strings.Contains(category, "politics") ||
+			strings.Contains(question, "election") ||
+			strings.Contains(question, "president") ||
+			strings.Contains(question, "congress") ||
+			strings.Contains(question, "senate") ||
+			strings.Contains(question, "mayor") ||
+			strings.Contains(question, "governor")
+	case FilterSports:
+		return strings.Contains(category, "sports") ||
+			strings.Contains(question, "nba") ||
+			strings.Contains(question, "nfl") ||
+			strings.Contains(question, "fifa") ||
+			strings.Contains(question, "champion") ||
+			strings.Contains(question, "world series") ||
+			strings.Contains(question, "playoff")
+	case FilterEntertainment:
+		return strings.Contains(category, "entertainment") ||
+			strings.Contains(question, "movie") ||
+			strings.Contains(question, "oscar") ||
+			strings.Contains(question, "box office")
+	}
+	return false
+}
+
+func SortMarkets(markets []types.Market, by SortMode) {
+	switch by {
+	case SortVolume:
+		sort.Slice(markets, func(i, j int) bool {
+			volI := markets[i].GetVolume()
+			volJ := markets[j].GetVolume()
+			if volI == 0 && volJ == 0 {
+				return markets[i].Volume24hr > markets[j].Volume24hr
+			}
+			return volI > volJ
+		})
+	case SortChange:
+		sort.Slice(markets, func(i, j int) bool {
+			return markets[i].OneDayPriceChange > markets[j].OneDayPriceChange
+		})
+	case SortLiquidity:
+		sort.Slice(markets, func(i, j int) bool {
+			return markets[i].GetLiquidity() > markets[j].GetLiquidity()
+		})
+	case SortVolume24h:
+		sort.Slice(markets, func(i, j int) bool {
+			return markets[i].Volume24hr > markets[j].Volume24hr
+		})
+	case SortOdds:
+		sort.Slice(markets, func(i, j int) bool {
+			yesI, _ := ParseOdds(&markets[i])
+			yesJ, _ := ParseOdds(&markets[j])
+			return yesI > yesJ
+		})
+	}
+}
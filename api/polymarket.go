@@ -137,6 +137,7 @@ func calculateStats(markets []types.Market) types.GlobalStats {
 	stats.TopGainer = topGainer
 	stats.TopGainerChange = topGainerChange
 	stats.TopVolume = topVolume
+	stats.Categories = CategoryStats(markets)
 	
 	if len(markets) > 0 {
 		stats.AvgLiquidity = totalLiquidity / float64(len(markets))
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"polyterm/types"
+)
+
+type Window int
+
+const (
+	Window1h Window = iota
+	Window24h
+	Window1w
+	Window1mo
+)
+
+var WindowNames = []string{"1h", "24h", "1w", "1mo"}
+
+func PriceChange(m *types.Market, w Window) float64 {
+	switch w {
+	case Window1h:
+		return m.OneHourPriceChange
+	case Window1w:
+		return m.OneWeekPriceChange
+	case Window1mo:
+		return m.OneMonthPriceChange
+	}
+	return m.OneDayPriceChange
+}
+
+func WindowVolume(m *types.Market, w Window) float64 {
+	switch w {
+	case Window1w:
+		return m.Volume1wk
+	case Window1mo:
+		return m.Volume1mo
+	}
+	return m.Volume24hr
+}
+
+func TopByVolume(markets []types.Market, limit int) []types.Market {
+	sorted := make([]types.Market, len(markets))
+	copy(sorted, markets)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetVolume() > sorted[j].GetVolume()
+	})
+
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+func TopByWindowVolume(markets []types.Market, w Window, limit int) []types.Market {
+	sorted := make([]types.Market, len(markets))
+	copy(sorted, markets)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return WindowVolume(&sorted[i], w) > WindowVolume(&sorted[j], w)
+	})
+
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+func TopMovers(markets []types.Market, w Window, limit int) []types.Market {
+	sorted := make([]types.Market, len(markets))
+	copy(sorted, markets)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return math.Abs(PriceChange(&sorted[i], w)) > math.Abs(PriceChange(&sorted[j], w))
+	})
+
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+func TopMomentum(markets []types.Market, limit int) []types.Market {
+	sorted := make([]types.Market, len(markets))
+	copy(sorted, markets)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetMomentumScore() > sorted[j].GetMomentumScore()
+	})
+
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+func MostEngaged(markets []types.Market, limit int) []types.Market {
+	sorted := make([]types.Market, len(markets))
+	copy(sorted, markets)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetEngagementScore() > sorted[j].GetEngagementScore()
+	})
+
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+func TightestSpreads(markets []types.Market, limit int) []types.Market {
+	filtered := make([]types.Market, 0)
+	for _, m := range markets {
+		if m.GetSpread() > 0 {
+			filtered = append(filtered, m)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].GetSpread() < filtered[j].GetSpread()
+	})
+
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered
+}
+
+func HighestOpenInterest(markets []types.Market, limit int) []types.Market {
+	filtered := make([]types.Market, 0)
+	for _, m := range markets {
+		if m.OpenInterest > 0 {
+			filtered = append(filtered, m)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].OpenInterest > filtered[j].OpenInterest
+	})
+
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered
+}
+
+func ClosingSoon(markets []types.Market, now time.Time, limit int) []types.Market {
+	type closing struct {
+		market types.Market
+		end    time.Time
+	}
+	var upcoming []closing
+	for _, m := range markets {
+		end, err := time.Parse(time.RFC3339, m.EndDate)
+		if err == nil && end.After(now) {
+			upcoming = append(upcoming, closing{m, end})
+		}
+	}
+
+	sort.Slice(upcoming, func(i, j int) bool {
+		return upcoming[i].end.Before(upcoming[j].end)
+	})
+
+	filtered := make([]types.Market, 0, min(limit, len(upcoming)))
+	for i := 0; i < len(upcoming) && i < limit; i++ {
+		filtered = append(filtered, upcoming[i].market)
+	}
+	return filtered
+}
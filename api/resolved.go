@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"polyterm/types"
+)
+
+const (
+	resolvedPrice = 0.99
+	idBatchSize   = 50
+)
+
+var closeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02",
+}
+
+func FetchMarketsByID(ctx context.Context, ids []string) ([]types.Market, error) {
+	var markets []types.Market
+	for start := 0; start < len(ids); start += idBatchSize {
+		end := min(len(ids), start+idBatchSize)
+		q := url.Values{}
+		q.Set("limit", strconv.Itoa(end-start))
+		for _, id := range ids[start:end] {
+			q.Add("id", id)
+		}
+
+		var batch []types.Market
+		if err := fetchWithTimeout(ctx, BaseURL+"/markets?"+q.Encode(), &batch); err != nil {
+			return markets, fmt.Errorf("markets by id: %w", err)
+		}
+		markets = append(markets, batch...)
+	}
+	return markets, nil
+}
+
+func fetchWithTimeout(ctx context.Context, url string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	return getJSON(ctx, url, v)
+}
+
+func Winner(m *types.Market) (int, bool) {
+	if !m.Closed {
+		return 0, false
+	}
+	for i, p := range m.GetOutcomePrices() {
+		if v, err := strconv.ParseFloat(p, 64); err == nil && v >= resolvedPrice {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func CloseTime(m *types.Market) time.Time {
+	for _, s := range []string{m.ClosedTime, m.EndDate} {
+		for _, layout := range closeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func FetchClosedMarkets(ctx context.Context, limit int) ([]types.Market, error) {
+	var markets []types.Market
+	url := fmt.Sprintf("%s/markets?closed=true&limit=%d&order=closedTime&ascending=false", BaseURL, limit)
+	if err := fetchWithTimeout(ctx, url, &markets); err != nil {
+		return nil, fmt.Errorf("closed markets: %w", err)
+	}
+
+	sort.SliceStable(markets, func(i, j int) bool {
+		return CloseTime(&markets[i]).After(CloseTime(&markets[j]))
+	})
+	return markets, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"polyterm/backtest"
+	"polyterm/config"
+	"polyterm/store"
+	"polyterm/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runBacktest(args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the report as JSON instead of opening the TUI report")
+	from := fs.String("from", "", "first day to replay (YYYY-MM-DD)")
+	to := fs.String("to", "", "last day to replay (YYYY-MM-DD)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: polyterm backtest [--json] [--from YYYY-MM-DD] [--to YYYY-MM-DD] <rules.json>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("backtest needs exactly one rule file")
+	}
+
+	var start, end time.Time
+	var err error
+	if *from != "" {
+		if start, err = time.ParseInLocation("2006-01-02", *from, time.Local); err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+	}
+	if *to != "" {
+		if end, err = time.ParseInLocation("2006-01-02", *to, time.Local); err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+		end = end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	strategy, err := backtest.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	st, err := store.Open(store.Dir(), store.DefaultRetention)
+	if err != nil {
+		return fmt.Errorf("opening snapshot history: %w", err)
+	}
+
+	rules := strategy.Rules
+	account := backtest.Account{Cash: rules.Cash, Stake: rules.Stake, MaxPositions: rules.MaxPositions}
+	report, err := backtest.Run(context.Background(), st, strategy, account, rules.Name, start, end)
+	if err != nil {
+		return err
+	}
+	for _, w := range report.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	model, err := ui.NewBacktestModel(cfg, report)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(model, tea.WithAltScreen()).Run()
+	return err
+}
@@ -0,0 +1,326 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"polyterm/api"
+	"polyterm/store"
+	"polyterm/types"
+)
+
+const changeLookback = 25 * time.Hour
+
+type Account struct {
+	Cash         float64
+	Stake        float64
+	MaxPositions int
+}
+
+type Position struct {
+	MarketID   string    `json:"marketId"`
+	Side       Side      `json:"side"`
+	EntryAt    time.Time `json:"entryAt"`
+	EntryPrice float64   `json:"entryPrice"`
+	Shares     float64   `json:"shares"`
+}
+
+func (p *Position) Return(price float64) float64 {
+	if p.EntryPrice == 0 || price <= 0 {
+		return 0
+	}
+	return price/p.EntryPrice - 1
+}
+
+type Trade struct {
+	Position
+	Question  string    `json:"question,omitempty"`
+	ExitAt    time.Time `json:"exitAt"`
+	ExitPrice float64   `json:"exitPrice"`
+	PnL       float64   `json:"pnl"`
+	Reason    string    `json:"reason"`
+	Open      bool      `json:"open,omitempty"`
+}
+
+type EquityPoint struct {
+	At       time.Time `json:"t"`
+	Equity   float64   `json:"equity"`
+	Drawdown float64   `json:"drawdown"`
+}
+
+type Report struct {
+	Strategy    string        `json:"strategy"`
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Snapshots   int           `json:"snapshots"`
+	StartCash   float64       `json:"startCash"`
+	FinalEquity float64       `json:"finalEquity"`
+	Return      float64       `json:"return"`
+	MaxDrawdown float64       `json:"maxDrawdown"`
+	Trades      []Trade       `json:"trades"`
+	Closed      int           `json:"closed"`
+	Wins        int           `json:"wins"`
+	HitRate     float64       `json:"hitRate"`
+	Equity      []EquityPoint `json:"equity"`
+	Warnings    []string      `json:"warnings,omitempty"`
+}
+
+func entryPrice(side Side, m *types.Market) float64 {
+	if side == SideNo {
+		if m.BestBid <= 0 {
+			return 0
+		}
+		return 1 - m.BestBid
+	}
+	if m.BestAsk >= 1 {
+		return 0
+	}
+	return m.BestAsk
+}
+
+func exitPrice(side Side, m *types.Market) float64 {
+	if side == SideNo {
+		if m.BestAsk <= 0 || m.BestAsk >= 1 {
+			return 0
+		}
+		return 1 - m.BestAsk
+	}
+	return m.BestBid
+}
+
+type engine struct {
+	strategy Strategy
+	account  Account
+	cash     float64
+	open     map[string]*Position
+	marks    map[string]float64
+	recent   map[string][]store.Point
+	report   Report
+	peak     float64
+
+	questions map[string]string
+}
+
+func Run(ctx context.Context, st *store.Store, strategy Strategy, account Account, name string, from, to time.Time) (Report, error) {
+	e := newEngine(strategy, account, name)
+	err := st.Snapshots(from, to, func(snap store.Snapshot) error {
+		e.step(snap)
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	resolutions := make(map[string]store.Resolution)
+	questions := make(map[string]string)
+	for _, r := range st.Resolutions() {
+		resolutions[r.ID] = r
+		questions[r.ID] = r.Question
+	}
+	traded := make(map[string]bool)
+	for _, t := range e.report.Trades {
+		traded[t.MarketID] = true
+	}
+	for id := range e.open {
+		traded[id] = true
+	}
+	var missing []string
+	for id := range traded {
+		if _, ok := resolutions[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		markets, err := api.FetchMarketsByID(ctx, missing)
+		if err != nil {
+			e.report.Warnings = append(e.report.Warnings, fmt.Sprintf("looking up %d traded markets failed, their questions may be missing and unresolved open positions are marked at their last stored price: %v", len(missing), err))
+		}
+		for i := range markets {
+			questions[markets[i].ID] = markets[i].Question
+			if r, ok := store.NewResolution(&markets[i]); ok {
+				if r.ClosedAt.IsZero() {
+					r.ClosedAt = e.report.To
+				}
+				resolutions[r.ID] = r
+			}
+		}
+	}
+	e.questions = questions
+	e.settle(resolutions)
+	return e.report, nil
+}
+
+func newEngine(strategy Strategy, account Account, name string) *engine {
+	return &engine{
+		strategy: strategy,
+		account:  account,
+		cash:     account.Cash,
+		open:     make(map[string]*Position),
+		marks:    make(map[string]float64),
+		recent:   make(map[string][]store.Point),
+		report:   Report{Strategy: name, StartCash: account.Cash},
+		peak:     account.Cash,
+	}
+}
+
+func (e *engine) step(snap store.Snapshot) {
+	if e.report.Snapshots == 0 {
+		e.report.From = snap.At
+	}
+	e.report.To = snap.At
+	e.report.Snapshots++
+
+	for _, p := range snap.Points {
+		p.At = snap.At
+		m := e.market(p)
+
+		if pos, ok := e.open[p.ID]; ok {
+			price := exitPrice(pos.Side, &m)
+			if price > 0 {
+				e.marks[p.ID] = price
+			}
+			if reason, ok := e.strategy.Exit(pos, &m, snap.At); ok && price > 0 {
+				e.close(pos, snap.At, price, reason, false)
+			}
+			continue
+		}
+
+		if len(e.open) >= e.account.MaxPositions || e.cash < e.account.Stake {
+			continue
+		}
+		side, ok := e.strategy.Enter(&m)
+		if !ok {
+			continue
+		}
+		price := entryPrice(side, &m)
+		if price <= 0 {
+			continue
+		}
+		e.cash -= e.account.Stake
+		e.open[p.ID] = &Position{MarketID: p.ID, Side: side, EntryAt: snap.At, EntryPrice: price, Shares: e.account.Stake / price}
+		e.marks[p.ID] = exitPrice(side, &m)
+	}
+
+	e.recordEquity(snap.At)
+}
+
+func (e *engine) market(p store.Point) types.Market {
+	h := append(e.recent[p.ID], p)
+	for len(h) > 1 && p.At.Sub(h[0].At) > changeLookback {
+		h = h[1:]
+	}
+	e.recent[p.ID] = h
+
+	m := p.Market()
+	if m.OneDayPriceChange == 0 {
+		m.OneDayPriceChange = p.Yes - priceAgo(h, p.At, 24*time.Hour)
+	}
+	if m.OneHourPriceChange == 0 {
+		m.OneHourPriceChange = p.Yes - priceAgo(h, p.At, time.Hour)
+	}
+	return m
+}
+
+func priceAgo(h []store.Point, at time.Time, d time.Duration) float64 {
+	price := h[0].Yes
+	for _, p := range h {
+		if at.Sub(p.At) < d {
+			break
+		}
+		price = p.Yes
+	}
+	return price
+}
+
+func (e *engine) close(pos *Position, at time.Time, price float64, reason string, open bool) {
+	proceeds := pos.Shares * price
+	e.report.Trades = append(e.report.Trades, Trade{
+		Position:  *pos,
+		ExitAt:    at,
+		ExitPrice: price,
+		PnL:       proceeds - pos.Shares*pos.EntryPrice,
+		Reason:    reason,
+		Open:      open,
+	})
+	if !open {
+		e.cash += proceeds
+		delete(e.open, pos.MarketID)
+		delete(e.marks, pos.MarketID)
+	}
+}
+
+func (e *engine) equity() float64 {
+	total := e.cash
+	for id, pos := range e.open {
+		total += pos.Shares * e.marks[id]
+	}
+	return total
+}
+
+func (e *engine) recordEquity(at time.Time) {
+	eq := e.equity()
+	e.peak = max(e.peak, eq)
+	dd := 0.0
+	if e.peak > 0 {
+		dd = (e.peak - eq) / e.peak
+	}
+	e.report.MaxDrawdown = max(e.report.MaxDrawdown, dd)
+	e.report.Equity = append(e.report.Equity, EquityPoint{At: at, Equity: eq, Drawdown: dd})
+}
+
+func (e *engine) settle(resolutions map[string]store.Resolution) {
+	ids := make([]string, 0, len(e.open))
+	for id := range e.open {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	settled := false
+	for _, id := range ids {
+		pos := e.open[id]
+		r, ok := resolutions[id]
+		if !ok || r.ClosedAt.Before(pos.EntryAt) {
+			e.close(pos, e.report.To, e.marks[id], "open", true)
+			continue
+		}
+		price := 0.0
+		if r.Yes == (pos.Side == SideYes) {
+			price = 1
+		}
+		e.close(pos, r.ClosedAt, price, "resolved "+r.Outcome, false)
+		e.report.To = maxTime(e.report.To, r.ClosedAt)
+		settled = true
+	}
+	if settled {
+		e.recordEquity(e.report.To)
+	}
+
+	for i := range e.report.Trades {
+		t := &e.report.Trades[i]
+		t.Question = e.questions[t.MarketID]
+		if !t.Open {
+			e.report.Closed++
+			if t.PnL > 0 {
+				e.report.Wins++
+			}
+		}
+	}
+	if e.report.Closed > 0 {
+		e.report.HitRate = float64(e.report.Wins) / float64(e.report.Closed)
+	}
+
+	e.report.FinalEquity = e.equity()
+	if e.report.StartCash > 0 {
+		e.report.Return = e.report.FinalEquity/e.report.StartCash - 1
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,119 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"polyterm/store"
+)
+
+func snapshots() []store.Snapshot {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	point := func(id string, yes float64) store.Point {
+		return store.Point{ID: id, Yes: yes, BestBid: yes - 0.01, BestAsk: yes + 0.01}
+	}
+	var snaps []store.Snapshot
+	for i, prices := range [][2]float64{{0.40, 0.70}, {0.50, 0.75}, {0.60, 0.80}} {
+		snaps = append(snaps, store.Snapshot{
+			At:     start.Add(time.Duration(i) * time.Hour),
+			Points: []store.Point{point("a", prices[0]), point("b", prices[1])},
+		})
+	}
+	return snaps
+}
+
+type wantTrade struct {
+	id     string
+	reason string
+	exit   float64
+	pnl    float64
+	open   bool
+}
+
+func TestEngine(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       Rules
+		resolutions []store.Resolution
+		trades      []wantTrade
+		final       float64
+		hitRate     float64
+	}{
+		{
+			name:  "yes with take profit",
+			rules: Rules{Cash: 1000, Stake: 100, Entry: Entry{Side: "yes", When: "yes < 45"}, Exit: Exit{TakeProfit: 20}},
+			trades: []wantTrade{
+				{id: "a", reason: "take profit", exit: 0.59, pnl: 100 / 0.41 * (0.59 - 0.41)},
+			},
+			final:   1000 + 100/0.41*(0.59-0.41),
+			hitRate: 1,
+		},
+		{
+			name:  "no with exit rule",
+			rules: Rules{Cash: 1000, Stake: 100, Entry: Entry{Side: "no", When: "yes > 65"}, Exit: Exit{When: "yes >= 80"}},
+			trades: []wantTrade{
+				{id: "b", reason: "exit rule", exit: 0.19, pnl: 100 / 0.31 * (0.19 - 0.31)},
+			},
+			final:   1000 + 100/0.31*(0.19-0.31),
+			hitRate: 0,
+		},
+		{
+			name:        "held to resolution",
+			rules:       Rules{Cash: 1000, Stake: 100, Entry: Entry{Side: "yes", When: "yes > 0"}},
+			resolutions: []store.Resolution{{ID: "a", Question: "Will A happen?", Outcome: "Yes", Yes: true, ClosedAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}},
+			trades: []wantTrade{
+				{id: "a", reason: "resolved Yes", exit: 1, pnl: 100 / 0.41 * (1 - 0.41)},
+				{id: "b", reason: "open", exit: 0.79, pnl: 100 / 0.71 * (0.79 - 0.71), open: true},
+			},
+			final:   800 + 100/0.41 + 100/0.71*0.79,
+			hitRate: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewRuleStrategy(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := strategy.Rules
+			e := newEngine(strategy, Account{Cash: r.Cash, Stake: r.Stake, MaxPositions: r.MaxPositions}, tt.name)
+			for _, snap := range snapshots() {
+				e.step(snap)
+			}
+			resolutions := make(map[string]store.Resolution)
+			e.questions = make(map[string]string)
+			for _, res := range tt.resolutions {
+				resolutions[res.ID] = res
+				e.questions[res.ID] = res.Question
+			}
+			e.settle(resolutions)
+
+			report := e.report
+			if report.Snapshots != 3 {
+				t.Errorf("replayed %d snapshots, want 3", report.Snapshots)
+			}
+			if len(report.Trades) != len(tt.trades) {
+				t.Fatalf("got %d trades, want %d: %+v", len(report.Trades), len(tt.trades), report.Trades)
+			}
+			for i, want := range tt.trades {
+				got := report.Trades[i]
+				if got.MarketID != want.id || got.Reason != want.reason || got.Open != want.open {
+					t.Errorf("trade %d = %s %q open=%v, want %s %q open=%v", i, got.MarketID, got.Reason, got.Open, want.id, want.reason, want.open)
+				}
+				if math.Abs(got.ExitPrice-want.exit) > 1e-9 || math.Abs(got.PnL-want.pnl) > 1e-9 {
+					t.Errorf("trade %d exit %v pnl %v, want %v %v", i, got.ExitPrice, got.PnL, want.exit, want.pnl)
+				}
+				if got.Question != e.questions[got.MarketID] {
+					t.Errorf("trade %d question %q, want %q", i, got.Question, e.questions[got.MarketID])
+				}
+			}
+			if math.Abs(report.FinalEquity-tt.final) > 1e-9 {
+				t.Errorf("final equity %v, want %v", report.FinalEquity, tt.final)
+			}
+			if report.HitRate != tt.hitRate {
+				t.Errorf("hit rate %v, want %v", report.HitRate, tt.hitRate)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package backtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"polyterm/query"
+	"polyterm/types"
+)
+
+type Side string
+
+const (
+	SideYes Side = "yes"
+	SideNo  Side = "no"
+)
+
+type Strategy interface {
+	Enter(m *types.Market) (Side, bool)
+	Exit(pos *Position, m *types.Market, at time.Time) (string, bool)
+}
+
+type Rules struct {
+	Name         string  `json:"name"`
+	Cash         float64 `json:"cash"`
+	Stake        float64 `json:"stake"`
+	MaxPositions int     `json:"maxPositions"`
+	Entry        Entry   `json:"entry"`
+	Exit         Exit    `json:"exit"`
+}
+
+type Entry struct {
+	Side string `json:"side"`
+	When string `json:"when"`
+}
+
+type Exit struct {
+	When       string  `json:"when,omitempty"`
+	TakeProfit float64 `json:"takeProfit,omitempty"`
+	StopLoss   float64 `json:"stopLoss,omitempty"`
+	MaxHold    string  `json:"maxHold,omitempty"`
+}
+
+type RuleStrategy struct {
+	Rules   Rules
+	side    Side
+	entry   query.Expr
+	exit    query.Expr
+	maxHold time.Duration
+}
+
+func Load(path string) (*RuleStrategy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Rules
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid rule file %s: %w", path, err)
+	}
+	if r.Name == "" {
+		r.Name = strings.TrimSuffix(path, ".json")
+	}
+	return NewRuleStrategy(r)
+}
+
+func NewRuleStrategy(r Rules) (*RuleStrategy, error) {
+	if r.Cash == 0 {
+		r.Cash = 1000
+	}
+	if r.Stake == 0 {
+		r.Stake = r.Cash / 10
+	}
+	if r.MaxPositions == 0 {
+		r.MaxPositions = 10
+	}
+
+	s := &RuleStrategy{Rules: r, side: Side(strings.ToLower(r.Entry.Side))}
+	var errs []error
+	if s.side == "" {
+		s.side = SideYes
+	}
+	if s.side != SideYes && s.side != SideNo {
+		errs = append(errs, fmt.Errorf("entry side must be yes or no, got %q", r.Entry.Side))
+	}
+	if r.Cash < 0 || r.Stake < 0 || r.Stake > r.Cash {
+		errs = append(errs, fmt.Errorf("stake must be between 0 and cash (%g)", r.Cash))
+	}
+
+	if r.Entry.When == "" {
+		errs = append(errs, errors.New("entry: when is required"))
+	} else if expr, err := query.Parse(r.Entry.When); err != nil {
+		errs = append(errs, fmt.Errorf("entry: %w", err))
+	} else {
+		s.entry = expr
+	}
+	if r.Exit.When != "" {
+		if expr, err := query.Parse(r.Exit.When); err != nil {
+			errs = append(errs, fmt.Errorf("exit: %w", err))
+		} else {
+			s.exit = expr
+		}
+	}
+	if r.Exit.MaxHold != "" {
+		d, err := time.ParseDuration(r.Exit.MaxHold)
+		if err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("exit: invalid maxHold %q", r.Exit.MaxHold))
+		}
+		s.maxHold = d
+	}
+	return s, errors.Join(errs...)
+}
+
+func (s *RuleStrategy) Enter(m *types.Market) (Side, bool) {
+	return s.side, s.entry.Match(m)
+}
+
+func (s *RuleStrategy) Exit(pos *Position, m *types.Market, at time.Time) (string, bool) {
+	ret := pos.Return(exitPrice(pos.Side, m))
+	switch {
+	case s.Rules.Exit.TakeProfit > 0 && ret*100 >= s.Rules.Exit.TakeProfit:
+		return "take profit", true
+	case s.Rules.Exit.StopLoss > 0 && ret*100 <= -s.Rules.Exit.StopLoss:
+		return "stop loss", true
+	case s.maxHold > 0 && at.Sub(pos.EntryAt) >= s.maxHold:
+		return "max hold", true
+	case s.exit != nil && s.exit.Match(m):
+		return "exit rule", true
+	}
+	return "", false
+}
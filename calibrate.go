@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"polyterm/api"
+	"polyterm/calibration"
+	"polyterm/store"
+)
+
+func runCalibration(args []string) error {
+	fs := flag.NewFlagSet("calibration", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	offline := fs.Bool("offline", false, "report on recorded resolutions without checking for new ones")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	st, err := store.Open(store.Dir(), store.DefaultRetention)
+	if err != nil {
+		return fmt.Errorf("opening snapshot history: %w", err)
+	}
+
+	if !*offline {
+		now := time.Now()
+		ids := st.Unresolved(now)
+		markets, fetchErr := api.FetchMarketsByID(context.Background(), ids)
+		if fetchErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", fetchErr)
+		}
+		added, err := st.Resolve(markets)
+		if err != nil {
+			return fmt.Errorf("recording resolutions: %w", err)
+		}
+		if fetchErr == nil {
+			if err := st.Checked(ids, now); err != nil {
+				return fmt.Errorf("recording resolution checks: %w", err)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Checked %d tracked markets, recorded %d new resolutions\n", len(ids), len(added))
+	}
+
+	report := calibration.Build(st.Resolutions())
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return calibration.Write(os.Stdout, report)
+}
@@ -0,0 +1,207 @@
+package calibration
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"polyterm/store"
+)
+
+const (
+	Buckets  = 10
+	epsilon  = 0.001
+	barWidth = 30
+)
+
+type Horizon struct {
+	Label string
+	Min   time.Duration
+	Max   time.Duration
+}
+
+var Horizons = []Horizon{
+	{"< 1 day", 0, 24 * time.Hour},
+	{"1-7 days", 24 * time.Hour, 7 * 24 * time.Hour},
+	{"7-30 days", 7 * 24 * time.Hour, 30 * 24 * time.Hour},
+	{"> 30 days", 30 * 24 * time.Hour, 0},
+}
+
+type Bucket struct {
+	Lo       float64 `json:"lo"`
+	Hi       float64 `json:"hi"`
+	Count    int     `json:"count"`
+	Forecast float64 `json:"forecast"`
+	Realized float64 `json:"realized"`
+}
+
+type Score struct {
+	Label     string   `json:"label"`
+	Markets   int      `json:"markets"`
+	Forecasts int      `json:"forecasts"`
+	Brier     float64  `json:"brier"`
+	LogLoss   float64  `json:"logLoss"`
+	Buckets   []Bucket `json:"buckets"`
+}
+
+type Report struct {
+	Resolved   int     `json:"resolved"`
+	Overall    Score   `json:"overall"`
+	ByCategory []Score `json:"byCategory"`
+	ByHorizon  []Score `json:"byHorizon"`
+}
+
+type forecast struct {
+	market   string
+	category string
+	horizon  int
+	p        float64
+	outcome  float64
+}
+
+func (h Horizon) contains(d time.Duration) bool {
+	return d >= h.Min && (h.Max == 0 || d < h.Max)
+}
+
+func forecasts(r store.Resolution) []forecast {
+	outcome := 0.0
+	if r.Yes {
+		outcome = 1
+	}
+
+	var out []forecast
+	for h, horizon := range Horizons {
+		for i := len(r.Forecasts) - 1; i >= 0; i-- {
+			f := r.Forecasts[i]
+			if horizon.contains(r.ClosedAt.Sub(f.At)) {
+				out = append(out, forecast{market: r.ID, category: r.Category, horizon: h, p: f.Yes, outcome: outcome})
+				break
+			}
+		}
+	}
+	return out
+}
+
+func Build(resolutions []store.Resolution) Report {
+	var all []forecast
+	for _, r := range resolutions {
+		all = append(all, forecasts(r)...)
+	}
+
+	report := Report{Resolved: len(resolutions), Overall: score("All markets", all)}
+
+	byCategory := make(map[string][]forecast)
+	for _, f := range all {
+		byCategory[f.category] = append(byCategory[f.category], f)
+	}
+	for name, fs := range byCategory {
+		report.ByCategory = append(report.ByCategory, score(name, fs))
+	}
+	sort.Slice(report.ByCategory, func(i, j int) bool {
+		a, b := report.ByCategory[i], report.ByCategory[j]
+		if a.Forecasts != b.Forecasts {
+			return a.Forecasts > b.Forecasts
+		}
+		return a.Label < b.Label
+	})
+
+	for h, horizon := range Horizons {
+		var fs []forecast
+		for _, f := range all {
+			if f.horizon == h {
+				fs = append(fs, f)
+			}
+		}
+		report.ByHorizon = append(report.ByHorizon, score(horizon.Label, fs))
+	}
+	return report
+}
+
+func score(label string, fs []forecast) Score {
+	s := Score{Label: label, Forecasts: len(fs), Buckets: make([]Bucket, Buckets)}
+	for i := range s.Buckets {
+		s.Buckets[i].Lo = float64(i) / Buckets
+		s.Buckets[i].Hi = float64(i+1) / Buckets
+	}
+	if len(fs) == 0 {
+		return s
+	}
+
+	markets := make(map[string]bool)
+	for _, f := range fs {
+		markets[f.market] = true
+		s.Brier += (f.p - f.outcome) * (f.p - f.outcome)
+
+		p := math.Max(epsilon, math.Min(1-epsilon, f.p))
+		s.LogLoss -= f.outcome*math.Log(p) + (1-f.outcome)*math.Log(1-p)
+
+		b := &s.Buckets[min(Buckets-1, int(f.p*Buckets))]
+		b.Count++
+		b.Forecast += f.p
+		b.Realized += f.outcome
+	}
+	s.Markets = len(markets)
+	s.Brier /= float64(len(fs))
+	s.LogLoss /= float64(len(fs))
+	for i := range s.Buckets {
+		if b := &s.Buckets[i]; b.Count > 0 {
+			b.Forecast /= float64(b.Count)
+			b.Realized /= float64(b.Count)
+		}
+	}
+	return s
+}
+
+func Write(w io.Writer, r Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Resolved markets: %d  Forecasts: %d  (one per market per time-to-close bucket)\n\n", r.Resolved, r.Overall.Forecasts)
+	if r.Overall.Forecasts == 0 {
+		b.WriteString("No resolved markets with stored price history yet. Keep polyterm running to collect snapshots.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "Brier score %.4f  Log loss %.4f\n\n", r.Overall.Brier, r.Overall.LogLoss)
+	b.WriteString("RELIABILITY  (bar: realized frequency, │: mean forecast)\n")
+	for _, bucket := range r.Overall.Buckets {
+		fmt.Fprintf(&b, "%3.0f-%3.0f%%  n=%-5d", bucket.Lo*100, bucket.Hi*100, bucket.Count)
+		if bucket.Count == 0 {
+			b.WriteString("\n")
+			continue
+		}
+		fmt.Fprintf(&b, "forecast %5.1f%%  realized %5.1f%%  %s\n", bucket.Forecast*100, bucket.Realized*100, reliabilityBar(bucket))
+	}
+
+	writeScores(&b, "BY CATEGORY", r.ByCategory)
+	writeScores(&b, "BY TIME TO CLOSE", r.ByHorizon)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func reliabilityBar(b Bucket) string {
+	bar := []rune(strings.Repeat("█", int(b.Realized*barWidth+0.5)) + strings.Repeat(" ", barWidth))[:barWidth]
+	bar[min(barWidth-1, int(b.Forecast*barWidth))] = '│'
+	return "|" + string(bar) + "|"
+}
+
+func writeScores(b *strings.Builder, title string, scores []Score) {
+	fmt.Fprintf(b, "\n%s\n%-24s %8s %10s %8s %9s\n", title, "", "Markets", "Forecasts", "Brier", "Log loss")
+	for _, s := range scores {
+		if s.Forecasts == 0 {
+			fmt.Fprintf(b, "%-24s %8d %10d %8s %9s\n", truncate(s.Label, 24), 0, 0, "-", "-")
+			continue
+		}
+		fmt.Fprintf(b, "%-24s %8d %10d %8.4f %9.4f\n", truncate(s.Label, 24), s.Markets, s.Forecasts, s.Brier, s.LogLoss)
+	}
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
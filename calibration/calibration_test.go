@@ -0,0 +1,92 @@
+package calibration
+
+import (
+	"math"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"polyterm/store"
+)
+
+func TestBuildScores(t *testing.T) {
+	closed := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := func(d time.Duration) time.Time { return closed.Add(-d) }
+	resolutions := []store.Resolution{
+		{ID: "a", Category: "Crypto", Yes: true, ClosedAt: closed, Forecasts: []store.Forecast{
+			{At: before(3 * 24 * time.Hour), Yes: 0.6},
+			{At: before(2 * time.Hour), Yes: 0.8},
+		}},
+		{ID: "b", Category: "Politics", Yes: false, ClosedAt: closed, Forecasts: []store.Forecast{
+			{At: before(40 * 24 * time.Hour), Yes: 0.5},
+			{At: before(time.Hour), Yes: 0.3},
+		}},
+	}
+	r := Build(resolutions)
+
+	tests := []struct {
+		name           string
+		got            Score
+		markets, n     int
+		brier, logLoss float64
+	}{
+		{"All markets", r.Overall, 2, 4, 0.135, -(math.Log(0.8) + math.Log(0.6) + math.Log(0.7) + math.Log(0.5)) / 4},
+		{"< 1 day", r.ByHorizon[0], 2, 2, 0.065, -(math.Log(0.8) + math.Log(0.7)) / 2},
+		{"1-7 days", r.ByHorizon[1], 1, 1, 0.16, -math.Log(0.6)},
+		{"7-30 days", r.ByHorizon[2], 0, 0, 0, 0},
+		{"> 30 days", r.ByHorizon[3], 1, 1, 0.25, -math.Log(0.5)},
+	}
+	for _, tt := range tests {
+		if tt.got.Label != tt.name {
+			t.Errorf("label = %q, want %q", tt.got.Label, tt.name)
+		}
+		if tt.got.Markets != tt.markets || tt.got.Forecasts != tt.n {
+			t.Errorf("%s: markets/forecasts = %d/%d, want %d/%d", tt.name, tt.got.Markets, tt.got.Forecasts, tt.markets, tt.n)
+		}
+		if math.Abs(tt.got.Brier-tt.brier) > 1e-9 {
+			t.Errorf("%s: Brier = %v, want %v", tt.name, tt.got.Brier, tt.brier)
+		}
+		if math.Abs(tt.got.LogLoss-tt.logLoss) > 1e-9 {
+			t.Errorf("%s: log loss = %v, want %v", tt.name, tt.got.LogLoss, tt.logLoss)
+		}
+	}
+
+	for _, want := range []struct {
+		bucket, count int
+	}{{3, 1}, {5, 1}, {6, 1}, {8, 1}, {0, 0}, {9, 0}} {
+		if got := r.Overall.Buckets[want.bucket].Count; got != want.count {
+			t.Errorf("bucket %d count = %d, want %d", want.bucket, got, want.count)
+		}
+	}
+	if len(r.ByCategory) != 2 {
+		t.Fatalf("got %d categories, want 2", len(r.ByCategory))
+	}
+}
+
+func TestLogLossClampsCertainForecasts(t *testing.T) {
+	s := score("x", []forecast{{market: "a", p: 1, outcome: 0}})
+	if want := -math.Log(epsilon); math.Abs(s.LogLoss-want) > 1e-9 {
+		t.Errorf("log loss = %v, want %v", s.LogLoss, want)
+	}
+	if s.Brier != 1 {
+		t.Errorf("Brier = %v, want 1", s.Brier)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"Crypto", 24, "Crypto"},
+		{"Élections présidentielles françaises", 24, "Élections présidentie..."},
+		{"東京都知事選挙の投票率が六割を超えるか", 10, "東京都知事選挙..."},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.in, tt.n)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
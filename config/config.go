@@ -0,0 +1,147 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"polyterm/alerts"
+)
+
+type View struct {
+	Name   string `json:"name"`
+	Search string `json:"search,omitempty"`
+	Filter string `json:"filter,omitempty"`
+	Sort   string `json:"sort,omitempty"`
+}
+
+type Keymap struct {
+	Preset   string              `json:"preset,omitempty"`
+	Bindings map[string][]string `json:"bindings,omitempty"`
+}
+
+type Palette struct {
+	Base   string            `json:"base,omitempty"`
+	Colors map[string]string `json:"colors"`
+}
+
+type Widget struct {
+	Type   string `json:"type"`
+	Title  string `json:"title,omitempty"`
+	Metric string `json:"metric,omitempty"`
+	Window string `json:"window,omitempty"`
+	Filter string `json:"filter,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Width  int    `json:"width,omitempty"`
+}
+
+type DashboardRow struct {
+	Height  int      `json:"height,omitempty"`
+	Widgets []Widget `json:"widgets"`
+}
+
+type Dashboard struct {
+	Rows []DashboardRow `json:"rows,omitempty"`
+}
+
+type Arbitrage struct {
+	FeeBps  float64 `json:"feeBps,omitempty"`
+	MinEdge float64 `json:"minEdge,omitempty"`
+}
+
+type Metrics struct {
+	Markets    []string `json:"markets,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Top        int      `json:"top,omitempty"`
+	MaxMarkets int      `json:"maxMarkets,omitempty"`
+}
+
+type Config struct {
+	Theme     string             `json:"theme,omitempty"`
+	Themes    map[string]Palette `json:"themes,omitempty"`
+	Keymap    Keymap             `json:"keymap,omitempty"`
+	Dashboard Dashboard          `json:"dashboard,omitempty"`
+	Arbitrage Arbitrage          `json:"arbitrage,omitempty"`
+	Metrics   Metrics            `json:"metrics,omitempty"`
+	Views     []View             `json:"views,omitempty"`
+	Alerts    []alerts.Rule      `json:"alerts,omitempty"`
+	Watchlist []string           `json:"watchlist,omitempty"`
+}
+
+func Dir() string {
+	if dir := os.Getenv("POLYTERM_HOME"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".polyterm"
+	}
+	return filepath.Join(dir, "polyterm")
+}
+
+func Path() string {
+	return filepath.Join(Dir(), "config.json")
+}
+
+func Load() (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", Path(), err)
+	}
+	return cfg, nil
+}
+
+func Save(cfg Config) error {
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(), append(data, '\n'), 0o644)
+}
+
+func (c Config) View(name string) (View, bool) {
+	for _, v := range c.Views {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return View{}, false
+}
+
+func (c *Config) SaveView(v View) {
+	for i := range c.Views {
+		if c.Views[i].Name == v.Name {
+			c.Views[i] = v
+			return
+		}
+	}
+	c.Views = append(c.Views, v)
+}
+
+func (c Config) Watching(id string) bool {
+	return slices.Contains(c.Watchlist, id)
+}
+
+func (c *Config) SetWatching(id string, watch bool) {
+	i := slices.Index(c.Watchlist, id)
+	switch {
+	case watch && i < 0:
+		c.Watchlist = append(c.Watchlist, id)
+	case !watch && i >= 0:
+		c.Watchlist = slices.Delete(c.Watchlist, i, i+1)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"polyterm/api"
+	"polyterm/config"
+	"polyterm/daemon"
+	"polyterm/exporter"
+	"polyterm/store"
+)
+
+func runDaemon(args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	addr := fs.String("addr", daemon.DefaultAddr, "listen address for the health, readiness and metrics endpoints")
+	interval := fs.Duration("interval", daemon.DefaultInterval, "time between market refreshes")
+	limit := fs.Int("limit", daemon.DefaultLimit, "markets to fetch per refresh")
+	resolveEvery := fs.Duration("resolve-every", daemon.DefaultResolveEvery, "time between resolution checks and snapshot pruning")
+	format := fs.String("log-format", "json", "log format: json or text")
+	level := fs.String("log-level", "info", "log level: debug, info, warn or error")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log, err := newLogger(*format, *level)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	st, err := store.Open(store.Dir(), store.DefaultRetention)
+	if err != nil {
+		return fmt.Errorf("opening snapshot history: %w", err)
+	}
+	if err := st.Prune(time.Now()); err != nil {
+		log.Warn("pruning snapshots failed", "err", err)
+	}
+
+	exp := exporter.New(cfg.Metrics)
+	d := daemon.New(cfg, st, log, daemon.Options{
+		Addr:         *addr,
+		Interval:     *interval,
+		Limit:        *limit,
+		ResolveEvery: *resolveEvery,
+		Cache:        api.NewCache(*interval/2, exp),
+	})
+	d.Handle("GET /metrics", exp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	go func() {
+		for sig := range signals {
+			if sig != syscall.SIGHUP {
+				log.Info("received signal", "signal", sig.String())
+				cancel()
+				return
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				log.Error("reloading config failed, keeping previous config", "err", err)
+				continue
+			}
+			exp.Configure(cfg.Metrics)
+			d.Reload(cfg)
+		}
+	}()
+
+	log.Info("daemon starting", "config", config.Path(), "store", store.Dir(), "interval", interval.String(), "limit", *limit)
+	return d.Run(ctx)
+}
+
+func newLogger(format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid --log-level: %w", err)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	}
+	return nil, fmt.Errorf("invalid --log-format %q (use json or text)", format)
+}
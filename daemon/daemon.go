@@ -0,0 +1,289 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"polyterm/alerts"
+	"polyterm/api"
+	"polyterm/config"
+	"polyterm/store"
+	"polyterm/types"
+)
+
+const (
+	DefaultAddr         = ":9090"
+	DefaultInterval     = 30 * time.Second
+	DefaultLimit        = 500
+	DefaultResolveEvery = time.Hour
+	staleAfter          = 3
+	shutdownTimeout     = 10 * time.Second
+)
+
+type Options struct {
+	Addr         string
+	Interval     time.Duration
+	Limit        int
+	ResolveEvery time.Duration
+	Cache        *api.Cache
+}
+
+type Status struct {
+	Ready       bool      `json:"ready"`
+	Reason      string    `json:"reason,omitempty"`
+	Started     time.Time `json:"started"`
+	LastFetch   time.Time `json:"lastFetch,omitzero"`
+	LastSuccess time.Time `json:"lastSuccess,omitzero"`
+	LastError   string    `json:"lastError,omitempty"`
+	Failures    int       `json:"consecutiveFailures"`
+	Markets     int       `json:"markets"`
+	Snapshots   int       `json:"snapshots"`
+	Alerts      int       `json:"alertRules"`
+}
+
+type Daemon struct {
+	opts   Options
+	log    *slog.Logger
+	store  *store.Store
+	mux    *http.ServeMux
+	mu     sync.RWMutex
+	alerts *alerts.Engine
+	status Status
+	done   bool
+}
+
+func New(cfg config.Config, st *store.Store, log *slog.Logger, opts Options) *Daemon {
+	if opts.Addr == "" {
+		opts.Addr = DefaultAddr
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+	if opts.ResolveEvery <= 0 {
+		opts.ResolveEvery = DefaultResolveEvery
+	}
+	if opts.Cache == nil {
+		opts.Cache = api.NewCache(0, nil)
+	}
+
+	d := &Daemon{
+		opts:   opts,
+		log:    log,
+		store:  st,
+		mux:    http.NewServeMux(),
+		alerts: alerts.NewEngine(cfg.Alerts),
+		status: Status{Started: time.Now()},
+	}
+	d.mux.HandleFunc("GET /healthz", d.handleHealth)
+	d.mux.HandleFunc("GET /readyz", d.handleReady)
+	return d
+}
+
+func (d *Daemon) Handle(pattern string, h http.Handler) {
+	d.mux.Handle(pattern, h)
+}
+
+func (d *Daemon) Reload(cfg config.Config) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !slices.Equal(d.alerts.Rules(), cfg.Alerts) {
+		d.alerts.SetRules(cfg.Alerts)
+	}
+	d.log.Info("config reloaded", "alert_rules", len(cfg.Alerts))
+}
+
+func (d *Daemon) Status() Status {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	s := d.status
+	s.Alerts = len(d.alerts.Rules())
+	switch {
+	case d.done:
+		s.Reason = "shutting down"
+	case s.LastSuccess.IsZero():
+		s.Reason = "no successful fetch yet"
+	case time.Since(s.LastSuccess) > staleAfter*d.opts.Interval:
+		s.Reason = "market data is stale"
+	default:
+		s.Ready = true
+	}
+	return s
+}
+
+func (d *Daemon) Run(ctx context.Context) error {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	srv := &http.Server{Addr: d.opts.Addr, Handler: d.mux, ReadHeaderTimeout: 5 * time.Second}
+	serveErr := make(chan error, 1)
+	go func() {
+		d.log.Info("http listening", "addr", d.opts.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+
+	loopDone := make(chan struct{})
+	go func() {
+		defer close(loopDone)
+		d.loop(ctx)
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-serveErr:
+		d.log.Error("http server failed", "err", err)
+	}
+
+	stop()
+	d.mu.Lock()
+	d.done = true
+	d.mu.Unlock()
+	d.log.Info("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if serr := srv.Shutdown(shutdownCtx); serr != nil {
+		err = errors.Join(err, serr)
+	}
+	<-loopDone
+	if wrote, ferr := d.store.Flush(); ferr != nil {
+		err = errors.Join(err, ferr)
+	} else if wrote {
+		d.mu.Lock()
+		d.status.Snapshots++
+		d.mu.Unlock()
+	}
+	if perr := d.store.Prune(time.Now()); perr != nil {
+		err = errors.Join(err, perr)
+	}
+	d.log.Info("stopped", "snapshots", d.Status().Snapshots)
+	return err
+}
+
+func (d *Daemon) loop(ctx context.Context) {
+	fetch := time.NewTicker(d.opts.Interval)
+	defer fetch.Stop()
+	resolve := time.NewTicker(d.opts.ResolveEvery)
+	defer resolve.Stop()
+
+	d.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-fetch.C:
+			d.refresh(ctx)
+		case <-resolve.C:
+			d.resolve(ctx)
+		}
+	}
+}
+
+func (d *Daemon) refresh(ctx context.Context) {
+	start := time.Now()
+	markets, _, err := d.opts.Cache.Markets(ctx, d.opts.Limit)
+	elapsed := time.Since(start)
+	if ctx.Err() != nil {
+		return
+	}
+
+	d.mu.Lock()
+	d.status.LastFetch = start
+	if err != nil {
+		d.status.LastError = err.Error()
+		d.status.Failures++
+		failures := d.status.Failures
+		d.mu.Unlock()
+		d.log.Error("fetch failed", "err", err, "duration_ms", elapsed.Milliseconds(), "consecutive_failures", failures)
+		return
+	}
+	d.status.LastSuccess = start
+	d.status.LastError = ""
+	d.status.Failures = 0
+	d.status.Markets = len(markets)
+	d.mu.Unlock()
+	d.log.Info("fetched markets", "markets", len(markets), "duration_ms", elapsed.Milliseconds())
+
+	if wrote, err := d.store.Record(start, markets); err != nil {
+		d.log.Error("recording snapshot failed", "err", err)
+	} else if wrote {
+		d.mu.Lock()
+		d.status.Snapshots++
+		d.mu.Unlock()
+	}
+
+	d.evaluate(markets)
+}
+
+func (d *Daemon) evaluate(markets []types.Market) {
+	d.mu.Lock()
+	events := d.alerts.Evaluate(markets)
+	d.mu.Unlock()
+	for _, e := range events {
+		d.log.Warn("alert triggered",
+			"rule", e.Rule.String(),
+			"market_id", e.Market.ID,
+			"question", e.Market.Question,
+			"metric", e.Rule.Metric,
+			"threshold", e.Rule.Threshold,
+			"value", e.Value,
+		)
+	}
+}
+
+func (d *Daemon) resolve(ctx context.Context) {
+	now := time.Now()
+	ids := d.store.Unresolved(now)
+	markets, fetchErr := api.FetchMarketsByID(ctx, ids)
+	if fetchErr != nil {
+		d.log.Warn("checking resolutions", "err", fetchErr)
+	}
+	added, err := d.store.Resolve(markets)
+	if err != nil {
+		d.log.Error("recording resolutions failed", "err", err)
+		return
+	}
+	if fetchErr == nil {
+		if err := d.store.Checked(ids, now); err != nil {
+			d.log.Error("recording resolution checks failed", "err", err)
+		}
+	}
+	d.log.Info("checked resolutions", "tracked", len(ids), "resolved", len(added))
+
+	if err := d.store.Prune(now); err != nil {
+		d.log.Error("pruning snapshots failed", "err", err)
+	}
+}
+
+func (d *Daemon) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (d *Daemon) handleReady(w http.ResponseWriter, r *http.Request) {
+	s := d.Status()
+	code := http.StatusOK
+	if !s.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, s)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"polyterm/api"
+	"polyterm/types"
+)
+
+type Field struct {
+	Name  string
+	Value func(m *types.Market) float64
+}
+
+var Fields = []Field{
+	{"yes", func(m *types.Market) float64 {
+		yes, _ := api.ParseOdds(m)
+		return yes / 100
+	}},
+	{"no", func(m *types.Market) float64 {
+		_, no := api.ParseOdds(m)
+		return no / 100
+	}},
+	{"bestBid", func(m *types.Market) float64 { return m.BestBid }},
+	{"bestAsk", func(m *types.Market) float64 { return m.BestAsk }},
+	{"lastTradePrice", func(m *types.Market) float64 { return m.LastTradePrice }},
+	{"spread", func(m *types.Market) float64 { return m.GetSpread() }},
+	{"oneHourPriceChange", func(m *types.Market) float64 { return m.OneHourPriceChange }},
+	{"oneDayPriceChange", func(m *types.Market) float64 { return m.OneDayPriceChange }},
+	{"volume", func(m *types.Market) float64 { return m.GetVolume() }},
+	{"volume24hr", func(m *types.Market) float64 { return m.Volume24hr }},
+	{"liquidity", func(m *types.Market) float64 { return m.GetLiquidity() }},
+	{"openInterest", func(m *types.Market) float64 { return m.OpenInterest }},
+	{"commentCount", func(m *types.Market) float64 { return float64(m.CommentCount) }},
+}
+
+type Delta struct {
+	From float64 `json:"from"`
+	To   float64 `json:"to"`
+}
+
+func (d Delta) Change() float64 {
+	return d.To - d.From
+}
+
+type Change struct {
+	ID     string           `json:"id"`
+	Fields map[string]Delta `json:"fields"`
+}
+
+type Result struct {
+	Added   []types.Market `json:"added,omitempty"`
+	Removed []types.Market `json:"removed,omitempty"`
+	Changed []Change       `json:"changed,omitempty"`
+}
+
+func (r Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+func Compare(prev, next []types.Market) Result {
+	before := make(map[string]*types.Market, len(prev))
+	for i := range prev {
+		before[prev[i].ID] = &prev[i]
+	}
+
+	var r Result
+	seen := make(map[string]bool, len(next))
+	for i := range next {
+		m := &next[i]
+		seen[m.ID] = true
+		old, ok := before[m.ID]
+		if !ok {
+			r.Added = append(r.Added, *m)
+			continue
+		}
+		if c, ok := compareMarket(old, m); ok {
+			r.Changed = append(r.Changed, c)
+		}
+	}
+	for i := range prev {
+		if !seen[prev[i].ID] {
+			r.Removed = append(r.Removed, prev[i])
+		}
+	}
+	return r
+}
+
+func compareMarket(old, m *types.Market) (Change, bool) {
+	var fields map[string]Delta
+	for _, f := range Fields {
+		from, to := f.Value(old), f.Value(m)
+		if from == to {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]Delta)
+		}
+		fields[f.Name] = Delta{From: from, To: to}
+	}
+	return Change{ID: m.ID, Fields: fields}, fields != nil
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"polyterm/api"
+	"polyterm/types"
+)
+
+var csvHeader = []string{
+	"id", "slug", "question", "category", "yes", "no",
+	"volume", "volume24h", "liquidity", "change24h", "spread", "end_date",
+}
+
+func CSV(w io.Writer, markets []types.Market) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for i := range markets {
+		m := &markets[i]
+		yes, no := api.ParseOdds(m)
+		record := []string{
+			m.ID,
+			m.GetSlug(),
+			m.Question,
+			m.Category,
+			formatFloat(yes),
+			formatFloat(no),
+			formatFloat(m.GetVolume()),
+			formatFloat(m.Volume24hr),
+			formatFloat(m.GetLiquidity()),
+			formatFloat(m.OneDayPriceChange),
+			formatFloat(m.GetSpread()),
+			m.EndDate,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func JSON(w io.Writer, markets []types.Market) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(markets)
+}
+
+func ToFile(path string, markets []types.Market) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = JSON(f, markets)
+	case ".csv", "":
+		err = CSV(f, markets)
+	default:
+		err = fmt.Errorf("unsupported export format %q", filepath.Ext(path))
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
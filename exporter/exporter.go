@@ -0,0 +1,226 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"polyterm/api"
+	"polyterm/config"
+	"polyterm/types"
+)
+
+const (
+	DefaultTop        = 50
+	DefaultMaxMarkets = 200
+	MaxMarketsLimit   = 1000
+	maxLabelLen       = 128
+)
+
+var fetchBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var marketGauges = []struct {
+	name  string
+	help  string
+	value func(m *types.Market) float64
+}{
+	{"polyterm_market_yes_price", "YES price of the market (0-1).", func(m *types.Market) float64 {
+		yes, _ := api.ParseOdds(m)
+		return yes / 100
+	}},
+	{"polyterm_market_volume_24h_usd", "Trading volume over the last 24 hours in USD.", func(m *types.Market) float64 { return m.Volume24hr }},
+	{"polyterm_market_liquidity_usd", "Order book liquidity in USD.", func(m *types.Market) float64 { return m.GetLiquidity() }},
+	{"polyterm_market_spread", "Best ask minus best bid.", func(m *types.Market) float64 { return m.GetSpread() }},
+	{"polyterm_market_open_interest_usd", "Open interest in USD.", func(m *types.Market) float64 { return m.OpenInterest }},
+}
+
+type Exporter struct {
+	mu          sync.Mutex
+	cfg         config.Metrics
+	markets     []types.Market
+	dropped     int
+	buckets     []uint64
+	fetchCount  uint64
+	fetchSum    float64
+	fetchErrors uint64
+	returned    int
+	cacheHits   uint64
+	lastSuccess time.Time
+}
+
+func New(cfg config.Metrics) *Exporter {
+	e := &Exporter{buckets: make([]uint64, len(fetchBuckets))}
+	e.Configure(cfg)
+	return e
+}
+
+func (e *Exporter) Configure(cfg config.Metrics) {
+	if cfg.Top <= 0 {
+		cfg.Top = DefaultTop
+	}
+	if cfg.MaxMarkets <= 0 {
+		cfg.MaxMarkets = DefaultMaxMarkets
+	}
+	cfg.MaxMarkets = min(cfg.MaxMarkets, MaxMarketsLimit)
+	e.mu.Lock()
+	e.cfg = cfg
+	e.mu.Unlock()
+}
+
+func (e *Exporter) ObserveFetch(d time.Duration, markets []types.Market, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	secs := d.Seconds()
+	e.fetchCount++
+	e.fetchSum += secs
+	for i, le := range fetchBuckets {
+		if secs <= le {
+			e.buckets[i]++
+		}
+	}
+	if err != nil {
+		e.fetchErrors++
+		return
+	}
+	e.returned = len(markets)
+	e.lastSuccess = time.Now()
+	e.markets, e.dropped = selectMarkets(e.cfg, markets)
+}
+
+func (e *Exporter) ObserveCacheHit() {
+	e.mu.Lock()
+	e.cacheHits++
+	e.mu.Unlock()
+}
+
+func selectMarkets(cfg config.Metrics, markets []types.Market) ([]types.Market, int) {
+	seen := make(map[string]bool)
+	var wanted []types.Market
+	add := func(m *types.Market) {
+		if !seen[m.ID] {
+			seen[m.ID] = true
+			wanted = append(wanted, *m)
+		}
+	}
+
+	byKey := make(map[string]*types.Market, len(markets)*2)
+	for i := range markets {
+		m := &markets[i]
+		byKey[m.ID] = m
+		if slug := m.GetSlug(); slug != "" {
+			byKey[slug] = m
+		}
+	}
+	for _, key := range cfg.Markets {
+		if m, ok := byKey[key]; ok {
+			add(m)
+		}
+	}
+
+	top := make([]*types.Market, 0, len(markets))
+	for i := range markets {
+		if inCategories(&markets[i], cfg.Categories) {
+			top = append(top, &markets[i])
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Volume24hr > top[j].Volume24hr })
+	for _, m := range top[:min(cfg.Top, len(top))] {
+		add(m)
+	}
+
+	if len(wanted) > cfg.MaxMarkets {
+		return wanted[:cfg.MaxMarkets], len(wanted) - cfg.MaxMarkets
+	}
+	return wanted, 0
+}
+
+func inCategories(m *types.Market, categories []string) bool {
+	if len(categories) == 0 {
+		return true
+	}
+	for _, c := range categories {
+		if strings.EqualFold(c, m.GetCategory()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	e.write(bw)
+	bw.Flush()
+}
+
+func (e *Exporter) write(w *bufio.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, g := range marketGauges {
+		header(w, g.name, g.help, "gauge")
+		for i := range e.markets {
+			m := &e.markets[i]
+			fmt.Fprintf(w, "%s{id=%s,slug=%s,category=%s} %s\n",
+				g.name, label(m.ID), label(m.GetSlug()), label(m.GetCategory()), value(g.value(m)))
+		}
+	}
+
+	header(w, "polyterm_fetch_duration_seconds", "Duration of upstream market fetches.", "histogram")
+	for i, le := range fetchBuckets {
+		fmt.Fprintf(w, "polyterm_fetch_duration_seconds_bucket{le=\"%s\"} %d\n", value(le), e.buckets[i])
+	}
+	fmt.Fprintf(w, "polyterm_fetch_duration_seconds_bucket{le=\"+Inf\"} %d\n", e.fetchCount)
+	fmt.Fprintf(w, "polyterm_fetch_duration_seconds_sum %s\n", value(e.fetchSum))
+	fmt.Fprintf(w, "polyterm_fetch_duration_seconds_count %d\n", e.fetchCount)
+
+	counter := func(name, help string, v uint64) {
+		header(w, name, help, "counter")
+		fmt.Fprintf(w, "%s %d\n", name, v)
+	}
+	gauge := func(name, help string, v float64) {
+		header(w, name, help, "gauge")
+		fmt.Fprintf(w, "%s %s\n", name, value(v))
+	}
+	counter("polyterm_fetch_errors_total", "Upstream market fetches that failed.", e.fetchErrors)
+	counter("polyterm_cache_hits_total", "Market requests served from the shared cache without an upstream fetch.", e.cacheHits)
+	gauge("polyterm_fetch_markets", "Markets returned by the last successful fetch.", float64(e.returned))
+	gauge("polyterm_exported_markets", "Markets exported with per-market gauges.", float64(len(e.markets)))
+	gauge("polyterm_dropped_markets", "Selected markets left out by the maxMarkets cardinality limit.", float64(e.dropped))
+	last := 0.0
+	if !e.lastSuccess.IsZero() {
+		last = float64(e.lastSuccess.UnixMilli()) / 1000
+	}
+	gauge("polyterm_last_fetch_success_timestamp_seconds", "Unix time of the last successful fetch.", last)
+}
+
+func header(w *bufio.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func label(s string) string {
+	if r := []rune(s); len(r) > maxLabelLen {
+		s = string(r[:maxLabelLen])
+	}
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+func value(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
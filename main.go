@@ -1,17 +1,126 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"time"
 
+	"polyterm/api"
+	"polyterm/config"
+	"polyterm/exporter"
+	"polyterm/session"
+	"polyterm/store"
 	"polyterm/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	p := tea.NewProgram(ui.NewModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "calibration":
+			if err := runCalibration(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "backtest":
+			if err := runBacktest(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "daemon":
+			if err := runDaemon(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "serve":
+			if err := runServe(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "replay":
+			if err := runReplay(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "open":
+			if err := runOpen(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	record := flag.String("record", "", "record every market refresh to `file` for polyterm replay (.gz to compress)")
+	metricsAddr := flag.String("metrics", "", "serve Prometheus metrics on `addr` (e.g. :9091) while the TUI runs")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	st, err := store.Open(store.Dir(), store.DefaultRetention)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: snapshot history disabled: %v\n", err)
+		st = nil
+	} else if err := st.Prune(time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: pruning snapshots: %v\n", err)
+	}
+
+	model, err := ui.NewModel(cfg, st)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *metricsAddr != "" {
+		exp := exporter.New(cfg.Metrics)
+		ln, err := net.Listen("tcp", *metricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: metrics listener: %v\n", err)
+			os.Exit(1)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", exp)
+		go http.Serve(ln, mux)
+		model = model.WithCache(api.NewCache(0, exp))
+	}
+
+	var recorder *session.Recorder
+	if *record != "" {
+		if recorder, err = session.Create(*record); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		model = model.WithRecorder(recorder)
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	_, err = p.Run()
+	if st != nil {
+		if _, ferr := st.Flush(); ferr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: saving snapshot history: %v\n", ferr)
+		}
+	}
+	if recorder != nil {
+		if cerr := recorder.Close(); cerr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: closing recording: %v\n", cerr)
+		} else {
+			fmt.Fprintf(os.Stderr, "Recorded %d frames to %s\n", recorder.Frames(), *record)
+		}
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"polyterm/api"
+	"polyterm/store"
+	"polyterm/types"
+)
+
+const (
+	MinSamples       = 10
+	AccelThreshold   = 3.0
+	ZScoreThreshold  = 3.0
+	AdjMoveThreshold = 15.0
+	MinVolume24h     = 1000.0
+	MaxMoveGap       = 3 * store.RecordInterval
+	referenceDepth   = 10000.0
+)
+
+type Signals struct {
+	VolumeAccel24h float64
+	VolumeAccel1w  float64
+	MoveZScore     float64
+	Samples        int
+	AdjustedMove   float64
+	Reasons        []string
+}
+
+func (s Signals) Unusual() bool {
+	return len(s.Reasons) > 0
+}
+
+func (s Signals) Score() float64 {
+	return max(s.VolumeAccel24h/AccelThreshold, math.Abs(s.MoveZScore)/ZScoreThreshold, s.AdjustedMove/AdjMoveThreshold)
+}
+
+func VolumeAcceleration(m *types.Market) (day, week float64) {
+	if m.Volume1wk > 0 {
+		day = m.Volume24hr / (m.Volume1wk / 7)
+	}
+	if m.Volume1mo > 0 {
+		week = m.Volume1wk / (m.Volume1mo / 4)
+	}
+	return day, week
+}
+
+func AdjustedMove(m *types.Market) float64 {
+	move := math.Abs(m.OneDayPriceChange) * 100
+	return move * math.Sqrt(m.GetLiquidity()/referenceDepth)
+}
+
+func scaledMove(from, to store.Point) (float64, bool) {
+	gap := to.At.Sub(from.At)
+	if gap <= 0 || gap > MaxMoveGap {
+		return 0, false
+	}
+	return (to.Yes - from.Yes) / math.Sqrt(gap.Minutes()), true
+}
+
+func MoveZScore(m *types.Market, history []store.Point, now time.Time) (z float64, samples int) {
+	if len(history) < 2 {
+		return 0, 0
+	}
+
+	moves := make([]float64, 0, len(history)-1)
+	for i := 1; i < len(history); i++ {
+		if d, ok := scaledMove(history[i-1], history[i]); ok {
+			moves = append(moves, d)
+		}
+	}
+	if len(moves) < MinSamples {
+		return 0, len(moves)
+	}
+
+	yes, _ := api.ParseOdds(m)
+	latest, ok := scaledMove(history[len(history)-1], store.Point{At: now, Yes: yes / 100})
+	if !ok {
+		return 0, len(moves)
+	}
+
+	mean := 0.0
+	for _, d := range moves {
+		mean += d
+	}
+	mean /= float64(len(moves))
+
+	variance := 0.0
+	for _, d := range moves {
+		variance += (d - mean) * (d - mean)
+	}
+	std := math.Sqrt(variance / float64(len(moves)-1))
+	if std == 0 {
+		return 0, len(moves)
+	}
+	return (latest - mean) / std, len(moves)
+}
+
+func Compute(m *types.Market, history []store.Point, now time.Time) Signals {
+	var s Signals
+	s.VolumeAccel24h, s.VolumeAccel1w = VolumeAcceleration(m)
+	s.MoveZScore, s.Samples = MoveZScore(m, history, now)
+	s.AdjustedMove = AdjustedMove(m)
+
+	if m.Volume24hr >= MinVolume24h && s.VolumeAccel24h >= AccelThreshold {
+		s.Reasons = append(s.Reasons, fmt.Sprintf("24h volume %.1fx weekly pace", s.VolumeAccel24h))
+	}
+	if m.Volume1wk >= MinVolume24h && s.VolumeAccel1w >= AccelThreshold {
+		s.Reasons = append(s.Reasons, fmt.Sprintf("weekly volume %.1fx monthly pace", s.VolumeAccel1w))
+	}
+	if math.Abs(s.MoveZScore) >= ZScoreThreshold {
+		s.Reasons = append(s.Reasons, fmt.Sprintf("price move %+.1fσ", s.MoveZScore))
+	}
+	if s.AdjustedMove >= AdjMoveThreshold {
+		s.Reasons = append(s.Reasons, fmt.Sprintf("liquidity-adjusted move %.1f", s.AdjustedMove))
+	}
+	return s
+}
+
+func ComputeAll(markets []types.Market, st *store.Store, now time.Time) map[string]Signals {
+	signals := make(map[string]Signals, len(markets))
+	for i := range markets {
+		var history []store.Point
+		if st != nil {
+			history = st.History(markets[i].ID)
+		}
+		signals[markets[i].ID] = Compute(&markets[i], history, now)
+	}
+	return signals
+}
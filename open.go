@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"polyterm/api"
+	"polyterm/config"
+	"polyterm/store"
+	"polyterm/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runOpen(args []string) error {
+	fs := flag.NewFlagSet("open", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: polyterm open <slug-or-url>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("open needs exactly one market slug or Polymarket URL")
+	}
+	ref, err := api.ParseMarketRef(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	st, err := store.Open(store.Dir(), store.DefaultRetention)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: snapshot history disabled: %v\n", err)
+		st = nil
+	} else if err := st.Prune(time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: pruning snapshots: %v\n", err)
+	}
+
+	model, err := ui.NewModel(cfg, st)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(model.WithOpen(ref), tea.WithAltScreen(), tea.WithMouseCellMotion()).Run()
+	if st != nil {
+		if _, ferr := st.Flush(); ferr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: saving snapshot history: %v\n", ferr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,329 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"polyterm/types"
+)
+
+type Expr interface {
+	Match(m *types.Market) bool
+	String() string
+}
+
+var textFields = map[string]func(m *types.Market) []string{
+	"category": func(m *types.Market) []string { return []string{m.GetCategory()} },
+	"question": func(m *types.Market) []string { return []string{m.Question} },
+	"slug":     func(m *types.Market) []string { return []string{m.GetSlug()} },
+	"outcome":  func(m *types.Market) []string { return m.GetOutcomes() },
+	"tag": func(m *types.Market) []string {
+		tags := make([]string, 0, 2*len(m.Tags))
+		for _, t := range m.Tags {
+			tags = append(tags, t.Label, t.Slug)
+		}
+		return tags
+	},
+}
+
+type andExpr struct{ left, right Expr }
+
+func (e andExpr) Match(m *types.Market) bool { return e.left.Match(m) && e.right.Match(m) }
+func (e andExpr) String() string             { return "(" + e.left.String() + " and " + e.right.String() + ")" }
+
+type orExpr struct{ left, right Expr }
+
+func (e orExpr) Match(m *types.Market) bool { return e.left.Match(m) || e.right.Match(m) }
+func (e orExpr) String() string             { return "(" + e.left.String() + " or " + e.right.String() + ")" }
+
+type notExpr struct{ inner Expr }
+
+func (e notExpr) Match(m *types.Market) bool { return !e.inner.Match(m) }
+func (e notExpr) String() string             { return "not " + e.inner.String() }
+
+type numberCmp struct {
+	metric Metric
+	op     string
+	value  float64
+}
+
+func (e numberCmp) Match(m *types.Market) bool {
+	v := e.metric.Value(m)
+	switch e.op {
+	case ">":
+		return v > e.value
+	case ">=":
+		return v >= e.value
+	case "<":
+		return v < e.value
+	case "<=":
+		return v <= e.value
+	case "=":
+		return v == e.value
+	case "!=":
+		return v != e.value
+	}
+	return false
+}
+
+func (e numberCmp) String() string {
+	return fmt.Sprintf("%s %s %g", e.metric.Name, e.op, e.value)
+}
+
+type textCmp struct {
+	field string
+	op    string
+	value string
+}
+
+func (e textCmp) Match(m *types.Market) bool {
+	matched := false
+	for _, s := range textFields[e.field](m) {
+		s = strings.ToLower(s)
+		if e.op == "~" {
+			matched = strings.Contains(s, e.value)
+		} else {
+			matched = s == e.value
+		}
+		if matched {
+			break
+		}
+	}
+	if e.op == "!=" {
+		return !matched
+	}
+	return matched
+}
+
+func (e textCmp) String() string {
+	return fmt.Sprintf("%s %s %q", e.field, e.op, e.value)
+}
+
+func Parse(s string) (Expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in filter %q", p.tokens[p.pos].text, s)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokString
+	tokOp
+	tokOpen
+	tokClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokOpen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokClose, ")"})
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated string in filter %q", s)
+			}
+			tokens = append(tokens, token{tokString, string(runes[i+1 : j])})
+			i = j + 1
+		case strings.ContainsRune("<>=!~&|", r):
+			j := i + 1
+			for j < len(runes) && strings.ContainsRune("<>=!~&|", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokOp, string(runes[i:j])})
+			i = j
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune("()<>=!~&|\"'", runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokWord, string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) accept(words ...string) bool {
+	t, ok := p.peek()
+	if !ok || t.kind == tokString {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or", "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and", "&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.accept("not", "!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner}, nil
+	}
+
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("filter ends unexpectedly")
+	}
+	if t.kind == tokOpen {
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokClose {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	if len(p.tokens)-p.pos < 3 {
+		return nil, fmt.Errorf("expected <field> <op> <value> near %q", p.tokens[p.pos].text)
+	}
+	field, op, value := p.tokens[p.pos], p.tokens[p.pos+1], p.tokens[p.pos+2]
+	p.pos += 3
+
+	if field.kind != tokWord {
+		return nil, fmt.Errorf("expected a field name, got %q", field.text)
+	}
+	if op.kind != tokOp {
+		return nil, fmt.Errorf("expected a comparison after %q, got %q", field.text, op.text)
+	}
+	if value.kind != tokWord && value.kind != tokString {
+		return nil, fmt.Errorf("expected a value after %q, got %q", op.text, value.text)
+	}
+	cmp := op.text
+	if cmp == "==" {
+		cmp = "="
+	}
+
+	name := strings.ToLower(field.text)
+	if _, ok := textFields[name]; ok {
+		switch cmp {
+		case "=", "!=", "~":
+		default:
+			return nil, fmt.Errorf("%s only supports =, != and ~, got %q", name, op.text)
+		}
+		return textCmp{field: name, op: cmp, value: strings.ToLower(value.text)}, nil
+	}
+
+	metric, ok := Lookup(field.text)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", field.text)
+	}
+	switch cmp {
+	case ">", ">=", "<", "<=", "=", "!=":
+	default:
+		return nil, fmt.Errorf("%s does not support %q", metric.Name, op.text)
+	}
+	n, err := ParseNumber(value.text)
+	if err != nil {
+		return nil, err
+	}
+	return numberCmp{metric: metric, op: cmp, value: n}, nil
+}
+
+func ParseNumber(s string) (float64, error) {
+	mult := 1.0
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(s, "$"), "%")
+	switch {
+	case strings.HasSuffix(trimmed, "k"), strings.HasSuffix(trimmed, "K"):
+		mult, trimmed = 1e3, trimmed[:len(trimmed)-1]
+	case strings.HasSuffix(trimmed, "m"), strings.HasSuffix(trimmed, "M"):
+		mult, trimmed = 1e6, trimmed[:len(trimmed)-1]
+	case strings.HasSuffix(trimmed, "b"), strings.HasSuffix(trimmed, "B"):
+		mult, trimmed = 1e9, trimmed[:len(trimmed)-1]
+	}
+	n, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return n * mult, nil
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"polyterm/types"
+)
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"volume > 1m", "volume > 1e+06"},
+		{"yes >= 50 and volume24h > 10k or category = crypto", `((yes >= 50 and volume24h > 10000) or category = "crypto")`},
+		{"yes >= 50 or volume24h > 10k and category = crypto", `(yes >= 50 or (volume24h > 10000 and category = "crypto"))`},
+		{"(yes >= 50 or volume24h > 10k) and category = crypto", `((yes >= 50 or volume24h > 10000) and category = "crypto")`},
+		{"not yes > 90 and spread < 0.05", "(not yes > 90 and spread < 0.05)"},
+		{"! (yes > 90 || no > 90)", "not (yes > 90 or no > 90)"},
+		{"question ~ 'Bitcoin ETF' && liquidity >= $5k", `(question ~ "bitcoin etf" and liquidity >= 5000)`},
+		{"Category == Politics", `category = "politics"`},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := expr.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "empty filter expression"},
+		{"   ", "empty filter expression"},
+		{"volume >", "expected <field> <op> <value>"},
+		{"(yes > 50", "missing closing parenthesis"},
+		{"yes > 50)", `unexpected ")"`},
+		{"yes > 50 and", "filter ends unexpectedly"},
+		{"question > 5", "question only supports =, != and ~"},
+		{"yes ~ 50", `yes does not support "~"`},
+		{"bogus > 5", `unknown field "bogus"`},
+		{"yes > lots", `invalid number "lots"`},
+		{"question = 'open", "unterminated string"},
+		{"> 5 5", "expected a field name"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want error containing %q", tt.in, tt.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	m := types.Market{
+		Question:         "Will Bitcoin hit $150k?",
+		OutcomesStr:      `["Yes","No"]`,
+		OutcomePricesStr: `["0.62","0.38"]`,
+		VolumeNum:        2_500_000,
+		Tags:             []types.Tag{{Label: "Crypto", Slug: "crypto"}},
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"yes > 60", true},
+		{"yes > 60 and no > 60", false},
+		{"no > 60 or volume >= 2.5m", true},
+		{"not volume >= 2.5m", false},
+		{"category = crypto", true},
+		{"category != crypto", false},
+		{"tag = crypto and question ~ bitcoin", true},
+		{"outcome = maybe", false},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.expr, err)
+		}
+		if got := expr.Match(&m); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"42", 42},
+		{"$1.5k", 1500},
+		{"2M", 2e6},
+		{"0.3b", 3e8},
+		{"65%", 65},
+	}
+	for _, tt := range tests {
+		got, err := ParseNumber(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseNumber(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
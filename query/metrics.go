@@ -0,0 +1,81 @@
+package query
+
+import (
+	"sort"
+	"strings"
+
+	"polyterm/api"
+	"polyterm/types"
+)
+
+type Kind int
+
+const (
+	KindNumber Kind = iota
+	KindCurrency
+	KindPercent
+	KindPrice
+)
+
+type Metric struct {
+	Name  string
+	Label string
+	Kind  Kind
+	Value func(m *types.Market) float64
+}
+
+var metrics = map[string]Metric{}
+
+func register(name, label string, kind Kind, value func(m *types.Market) float64) {
+	metrics[name] = Metric{Name: name, Label: label, Kind: kind, Value: value}
+}
+
+func init() {
+	register("yes", "YES", KindPercent, func(m *types.Market) float64 {
+		yes, _ := api.ParseOdds(m)
+		return yes
+	})
+	register("no", "NO", KindPercent, func(m *types.Market) float64 {
+		_, no := api.ParseOdds(m)
+		return no
+	})
+	register("change1h", "1h Change", KindPercent, func(m *types.Market) float64 { return m.OneHourPriceChange * 100 })
+	register("change24h", "24h Change", KindPercent, func(m *types.Market) float64 { return m.OneDayPriceChange * 100 })
+	register("change1w", "1w Change", KindPercent, func(m *types.Market) float64 { return m.OneWeekPriceChange * 100 })
+	register("change1mo", "1mo Change", KindPercent, func(m *types.Market) float64 { return m.OneMonthPriceChange * 100 })
+	register("volume", "Total Volume", KindCurrency, func(m *types.Market) float64 { return m.GetVolume() })
+	register("volume24h", "24h Volume", KindCurrency, func(m *types.Market) float64 { return m.Volume24hr })
+	register("volume1w", "1w Volume", KindCurrency, func(m *types.Market) float64 { return m.Volume1wk })
+	register("volume1mo", "1mo Volume", KindCurrency, func(m *types.Market) float64 { return m.Volume1mo })
+	register("liquidity", "Liquidity", KindCurrency, func(m *types.Market) float64 { return m.GetLiquidity() })
+	register("openInterest", "Open Interest", KindCurrency, func(m *types.Market) float64 { return m.OpenInterest })
+	register("spread", "Spread", KindPrice, func(m *types.Market) float64 { return m.GetSpread() })
+	register("bestBid", "Best Bid", KindPrice, func(m *types.Market) float64 { return m.BestBid })
+	register("bestAsk", "Best Ask", KindPrice, func(m *types.Market) float64 { return m.BestAsk })
+	register("lastPrice", "Last Price", KindPrice, func(m *types.Market) float64 { return m.LastTradePrice })
+	register("comments", "Comments", KindNumber, func(m *types.Market) float64 { return float64(m.CommentCount) })
+	register("momentum", "Momentum", KindNumber, func(m *types.Market) float64 { return m.GetMomentumScore() * 100 })
+	register("engagement", "Engagement", KindNumber, func(m *types.Market) float64 { return m.GetEngagementScore() })
+	register("competitive", "Competitiveness", KindNumber, func(m *types.Market) float64 { return m.Competitive })
+}
+
+func Lookup(name string) (Metric, bool) {
+	if metric, ok := metrics[name]; ok {
+		return metric, true
+	}
+	for _, metric := range metrics {
+		if strings.EqualFold(metric.Name, name) {
+			return metric, true
+		}
+	}
+	return Metric{}, false
+}
+
+func Names() []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
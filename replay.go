@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"polyterm/config"
+	"polyterm/session"
+	"polyterm/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	speed := fs.Int("speed", 1, "initial playback speed (1-100)")
+	paused := fs.Bool("paused", false, "start paused on the first frame")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: polyterm replay [--speed N] [--paused] <recording>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("replay needs exactly one recording file")
+	}
+	if *speed < 1 || *speed > 100 {
+		return fmt.Errorf("--speed must be between 1 and 100, got %d", *speed)
+	}
+
+	frames, err := session.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	model, err := ui.NewReplayModel(cfg, frames, float64(*speed), !*paused)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run()
+	return err
+}
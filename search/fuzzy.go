@@ -0,0 +1,43 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+func Fuzzy(pattern, text string) (int, bool) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return 0, true
+	}
+
+	p := []rune(pattern)
+	t := []rune(strings.ToLower(text))
+	score := 0
+	streak := 0
+	pi := 0
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if p[pi] == ' ' {
+			pi++
+			if pi == len(p) {
+				break
+			}
+		}
+		if t[ti] != p[pi] {
+			streak = 0
+			continue
+		}
+		score++
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3
+		}
+		streak++
+		score += streak
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+func Highlight(text, query string) []bool {
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	runes := []rune(text)
+	marks := make([]bool, len(runes))
+	found := false
+
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		tok := strings.ToLower(string(runes[start:i]))
+		for _, term := range terms {
+			from, to, ok := matchSpan(term, tok)
+			if !ok {
+				continue
+			}
+			for j := start + from; j < start+to && j < i; j++ {
+				marks[j] = true
+			}
+			found = true
+		}
+		start = -1
+	}
+
+	if !found {
+		return nil
+	}
+	return marks
+}
+
+func matchSpan(term, tok string) (int, int, bool) {
+	n := len([]rune(tok))
+	switch {
+	case term == tok:
+		return 0, n, true
+	case strings.HasPrefix(tok, term):
+		return 0, len([]rune(term)), true
+	case utf8.RuneCountInString(term) >= 3 && strings.Contains(tok, term):
+		from := len([]rune(tok[:strings.Index(tok, term)]))
+		return from, from + len([]rune(term)), true
+	}
+	if matchScore(term, tok) == 0 {
+		return 0, 0, false
+	}
+	t := []rune(term)
+	if maxDist := typoTolerance(t); editDistance(t, []rune(tok), maxDist) > maxDist {
+		return 0, min(len(t), n), true
+	}
+	return 0, n, true
+}
@@ -0,0 +1,37 @@
+package search
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text, query string
+		want        string
+	}{
+		{"Will Bitcoin hit 100k?", "bitcoin", "     ^^^^^^^          "},
+		{"Will Bitcoin hit 100k?", "bit", "     ^^^              "},
+		{"São Paulo — mayor", "são", "^^^              "},
+		{"São Paulo — mayor", "paolo", "    ^^^^^        "},
+		{"Who wins São Paulo?", "paul", "             ^^^^  "},
+		{"東京 turnout", "東京", "^^        "},
+		{"Fed cuts rates", "ethereum", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			marks := Highlight(tt.text, tt.query)
+			got := ""
+			if marks != nil {
+				b := []rune(tt.text)
+				for i := range b {
+					b[i] = ' '
+					if marks[i] {
+						b[i] = '^'
+					}
+				}
+				got = string(b)
+			}
+			if got != tt.want {
+				t.Errorf("Highlight(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"polyterm/types"
+)
+
+const (
+	weightQuestion    = 1.0
+	weightSlug        = 0.8
+	weightOutcome     = 0.7
+	weightTag         = 0.7
+	weightCategory    = 0.7
+	weightDescription = 0.4
+
+	relevanceWeight = 0.75
+	volumeWeight    = 0.25
+)
+
+type posting struct {
+	doc    int
+	weight float64
+}
+
+type Index struct {
+	size     int
+	vocab    []string
+	postings [][]posting
+	volume   []float64
+}
+
+type Result struct {
+	Index int
+	Score float64
+}
+
+func NewIndex(markets []types.Market) *Index {
+	idx := &Index{
+		size:   len(markets),
+		volume: make([]float64, len(markets)),
+	}
+	ids := make(map[string]int)
+	maxLog := 0.0
+
+	for i := range markets {
+		market := &markets[i]
+		best := make(map[int]float64)
+		add := func(text string, weight float64) {
+			for _, tok := range Tokenize(text) {
+				id, ok := ids[tok]
+				if !ok {
+					id = len(idx.vocab)
+					ids[tok] = id
+					idx.vocab = append(idx.vocab, tok)
+					idx.postings = append(idx.postings, nil)
+				}
+				if weight > best[id] {
+					best[id] = weight
+				}
+			}
+		}
+
+		add(market.Question, weightQuestion)
+		add(market.GetSlug(), weightSlug)
+		add(market.Category, weightCategory)
+		for _, outcome := range market.GetOutcomes() {
+			add(outcome, weightOutcome)
+		}
+		for _, tag := range market.Tags {
+			add(tag.Label, weightTag)
+			add(tag.Slug, weightTag)
+		}
+		add(market.Description, weightDescription)
+
+		for id, weight := range best {
+			idx.postings[id] = append(idx.postings[id], posting{doc: i, weight: weight})
+		}
+
+		logVol := math.Log10(1 + market.GetVolume())
+		idx.volume[i] = logVol
+		if logVol > maxLog {
+			maxLog = logVol
+		}
+	}
+
+	if maxLog > 0 {
+		for i := range idx.volume {
+			idx.volume[i] /= maxLog
+		}
+	}
+
+	return idx
+}
+
+func (idx *Index) Search(query string) []Result {
+	terms := Tokenize(query)
+	if idx == nil || len(terms) == 0 {
+		return nil
+	}
+
+	total := make([]float64, idx.size)
+	matched := make([]int, idx.size)
+	best := make([]float64, idx.size)
+
+	for _, term := range terms {
+		for i := range best {
+			best[i] = 0
+		}
+		for id, tok := range idx.vocab {
+			score := matchScore(term, tok)
+			if score == 0 {
+				continue
+			}
+			for _, p := range idx.postings[id] {
+				if s := score * p.weight; s > best[p.doc] {
+					best[p.doc] = s
+				}
+			}
+		}
+		for i, s := range best {
+			if s > 0 {
+				total[i] += s
+				matched[i]++
+			}
+		}
+	}
+
+	results := make([]Result, 0)
+	for i := range total {
+		if matched[i] < len(terms) {
+			continue
+		}
+		relevance := total[i] / float64(len(terms))
+		results = append(results, Result{
+			Index: i,
+			Score: relevanceWeight*relevance + volumeWeight*idx.volume[i],
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func matchScore(term, tok string) float64 {
+	t, k := []rune(term), []rune(tok)
+	switch {
+	case term == tok:
+		return 1.0
+	case strings.HasPrefix(tok, term):
+		return 0.8 + 0.1*float64(len(t))/float64(len(k))
+	case len(t) >= 3 && strings.Contains(tok, term):
+		return 0.7
+	}
+
+	maxDist := typoTolerance(t)
+	if maxDist == 0 {
+		return 0
+	}
+	if d := abs(len(t) - len(k)); d <= maxDist {
+		if dist := editDistance(t, k, maxDist); dist <= maxDist {
+			return 0.65 - 0.15*float64(dist-1)
+		}
+	}
+	if len(k) > len(t) {
+		if dist := editDistance(t, k[:len(t)], 1); dist <= 1 {
+			return 0.5
+		}
+	}
+	return 0
+}
+
+func typoTolerance(term []rune) int {
+	switch {
+	case len(term) >= 8:
+		return 2
+	case len(term) >= 4:
+		return 1
+	}
+	return 0
+}
+
+func editDistance(a, b []rune, limit int) int {
+	if abs(len(a)-len(b)) > limit {
+		return limit + 1
+	}
+
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"testing"
+
+	"polyterm/types"
+)
+
+func TestSearchRanking(t *testing.T) {
+	markets := []types.Market{
+		{ID: "0", Question: "Will Bitcoin reach $200k in 2026?", VolumeNum: 1000},
+		{ID: "1", Question: "Will the Bitcoin ETF see record inflows?", VolumeNum: 1_000_000},
+		{ID: "2", Question: "Will Ethereum flip Bitcoin?", Description: "bitcoin dominance", VolumeNum: 10},
+		{ID: "3", Question: "Who wins the São Paulo mayoral race?", VolumeNum: 500},
+		{ID: "4", Question: "東京 election turnout above 60%?", VolumeNum: 500},
+		{ID: "5", Question: "Will the Fed cut rates in December?", VolumeNum: 5000},
+	}
+	idx := NewIndex(markets)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"bitcoin", []string{"1", "0", "2"}},
+		{"bitcoin etf", []string{"1"}},
+		{"bitcon", []string{"1", "0", "2"}},
+		{"sao", nil},
+		{"são", []string{"3"}},
+		{"paolo", []string{"3"}},
+		{"東京", []string{"4"}},
+		{"decmber", []string{"5"}},
+		{"", nil},
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := idx.Search(tt.query)
+			var got []string
+			for _, r := range results {
+				got = append(got, markets[r.Index].ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"kitten", "kitten", 2, 0},
+		{"kitten", "sitten", 2, 1},
+		{"kitten", "iktten", 2, 1},
+		{"paulo", "paolo", 1, 1},
+		{"são", "sao", 1, 1},
+		{"東京都", "東京", 1, 1},
+		{"abcdef", "uvwxyz", 2, 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b), tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"polyterm/config"
+	"polyterm/exporter"
+	"polyterm/server"
+	"polyterm/store"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("http", ":8080", "listen address for the REST API")
+	ttl := fs.Duration("ttl", server.DefaultTTL, "how long fetched markets are reused before hitting the upstream API again")
+	limit := fs.Int("limit", server.DefaultLimit, "markets to fetch per refresh")
+	noRecord := fs.Bool("no-record", false, "don't write snapshots to the history store (e.g. when a daemon is already recording)")
+	origins := fs.String("origins", "", "comma-separated origin host patterns (e.g. example.com,*.example.com) allowed to open WebSocket streams besides the server's own host")
+	format := fs.String("log-format", "text", "log format: json or text")
+	level := fs.String("log-level", "info", "log level: debug, info, warn or error")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	log, err := newLogger(*format, *level)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	st, err := store.Open(store.Dir(), store.DefaultRetention)
+	if err != nil {
+		return fmt.Errorf("opening snapshot history: %w", err)
+	}
+
+	rest := server.New(st, log, server.Options{
+		TTL:      *ttl,
+		Limit:    *limit,
+		Record:   !*noRecord,
+		Exporter: exporter.New(cfg.Metrics),
+		Origins:  splitList(*origins),
+	})
+	srv := &http.Server{Addr: *addr, Handler: rest, ReadHeaderTimeout: 5 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	go rest.Run(ctx)
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Info("http listening", "addr", *addr, "ttl", ttl.String(), "limit", *limit)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+		}
+		close(serveErr)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	log.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	serr := srv.Shutdown(shutdownCtx)
+	_, ferr := st.Flush()
+	return errors.Join(serr, ferr, st.Prune(time.Now()))
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
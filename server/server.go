@@ -0,0 +1,372 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"polyterm/api"
+	"polyterm/exporter"
+	"polyterm/metrics"
+	"polyterm/search"
+	"polyterm/store"
+	"polyterm/types"
+)
+
+const (
+	DefaultTTL       = 30 * time.Second
+	DefaultLimit     = 500
+	defaultPageSize  = 50
+	maxPageSize      = 500
+	defaultRankLimit = 10
+	maxRankLimit     = 50
+)
+
+type Options struct {
+	TTL      time.Duration
+	Limit    int
+	Record   bool
+	Exporter *exporter.Exporter
+	Origins  []string
+}
+
+type Server struct {
+	opts  Options
+	log   *slog.Logger
+	store *store.Store
+	cache *api.Cache
+	mux   *http.ServeMux
+
+	mu      sync.RWMutex
+	markets []types.Market
+	byID    map[string]int
+	index   *search.Index
+	signals map[string]metrics.Signals
+	stats   types.GlobalStats
+	updated time.Time
+	seq     uint64
+
+	subsMu sync.Mutex
+	subs   map[*subscriber]struct{}
+	done   chan struct{}
+}
+
+type Market struct {
+	types.Market
+	Yes      float64          `json:"yes"`
+	No       float64          `json:"no"`
+	Category string           `json:"categoryName"`
+	Signals  *metrics.Signals `json:"signals,omitempty"`
+}
+
+type RankEntry struct {
+	ID       string  `json:"id"`
+	Slug     string  `json:"slug"`
+	Question string  `json:"question"`
+	Yes      float64 `json:"yes"`
+	Value    float64 `json:"value"`
+}
+
+type HistoryPoint struct {
+	At time.Time `json:"t"`
+	store.Point
+}
+
+func New(st *store.Store, log *slog.Logger, opts Options) *Server {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultLimit
+	}
+
+	s := &Server{
+		opts:  opts,
+		log:   log,
+		store: st,
+		mux:   http.NewServeMux(),
+		subs:  make(map[*subscriber]struct{}),
+		done:  make(chan struct{}),
+	}
+	s.cache = api.NewCache(opts.TTL, s)
+	s.mux.HandleFunc("GET /markets", s.handleMarkets)
+	s.mux.HandleFunc("GET /markets/{id}", s.handleMarket)
+	s.mux.HandleFunc("GET /stats", s.handleStats)
+	s.mux.HandleFunc("GET /history/{id}", s.handleHistory)
+	s.mux.HandleFunc("GET /events", s.handleEvents)
+	s.mux.HandleFunc("GET /ws", s.handleWebSocket)
+	if opts.Exporter != nil {
+		s.mux.Handle("GET /metrics", opts.Exporter)
+	}
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	s.mux.ServeHTTP(w, r)
+	s.log.Debug("request", "method", r.Method, "path", r.URL.RequestURI(), "duration_ms", time.Since(start).Milliseconds())
+}
+
+func (s *Server) ObserveFetch(d time.Duration, markets []types.Market, err error) {
+	if s.opts.Exporter != nil {
+		s.opts.Exporter.ObserveFetch(d, markets, err)
+	}
+	if err != nil {
+		s.log.Error("fetch failed", "err", err, "duration_ms", d.Milliseconds())
+		return
+	}
+	s.log.Info("fetched markets", "markets", len(markets), "duration_ms", d.Milliseconds())
+
+	now := time.Now()
+	byID := make(map[string]int, len(markets))
+	for i := range markets {
+		byID[markets[i].ID] = i
+	}
+	signals := metrics.ComputeAll(markets, s.store, now)
+	if s.opts.Record {
+		if _, err := s.store.Record(now, markets); err != nil {
+			s.log.Error("recording snapshot failed", "err", err)
+		}
+	}
+
+	s.mu.Lock()
+	prev, prevByID := s.markets, s.byID
+	s.markets, s.byID, s.index, s.signals, s.updated = markets, byID, search.NewIndex(markets), signals, now
+	s.seq++
+	s.mu.Unlock()
+	s.publish(prev, prevByID)
+}
+
+func (s *Server) ObserveCacheHit() {
+	if s.opts.Exporter != nil {
+		s.opts.Exporter.ObserveCacheHit()
+	}
+}
+
+func (s *Server) refresh(ctx context.Context) error {
+	_, stats, err := s.cache.Markets(ctx, s.opts.Limit)
+	if err != nil {
+		s.mu.RLock()
+		stale := s.markets != nil
+		s.mu.RUnlock()
+		if stale {
+			return nil
+		}
+		return err
+	}
+	s.mu.Lock()
+	s.stats = stats
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Server) enrich(m *types.Market) Market {
+	yes, no := api.ParseOdds(m)
+	out := Market{Market: *m, Yes: yes / 100, No: no / 100, Category: m.GetCategory()}
+	if sig, ok := s.signals[m.ID]; ok && sig.Unusual() {
+		out.Signals = &sig
+	}
+	return out
+}
+
+func (s *Server) handleMarkets(w http.ResponseWriter, r *http.Request) {
+	if err := s.refresh(r.Context()); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	q := r.URL.Query()
+
+	filter := api.FilterAll
+	if v := q.Get("filter"); v != "" {
+		idx, ok := parseName(api.FilterNames, v)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown filter %q (available: %s)", v, strings.Join(api.FilterNames, ", ")))
+			return
+		}
+		filter = api.FilterMode(idx)
+	}
+	by := api.SortVolume
+	if v := q.Get("sort"); v != "" {
+		idx, ok := parseName(api.SortNames, v)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown sort %q (available: %s)", v, strings.Join(api.SortNames, ", ")))
+			return
+		}
+		by = api.SortMode(idx)
+	}
+	limit, err := intParam(q.Get("limit"), defaultPageSize, 1, maxPageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit: %w", err))
+		return
+	}
+	offset, err := intParam(q.Get("offset"), 0, 0, -1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("offset: %w", err))
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	listing := api.Listing(s.markets, s.index, q.Get("q"), filter, by)
+	if category := q.Get("category"); category != "" {
+		listing = slices.DeleteFunc(listing, func(m types.Market) bool {
+			return !strings.EqualFold(m.GetCategory(), category)
+		})
+	}
+	start := min(offset, len(listing))
+	page := listing[start : start+min(limit, len(listing)-start)]
+	out := make([]Market, len(page))
+	for i := range page {
+		out[i] = s.enrich(&page[i])
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"updated": s.updated,
+		"total":   len(listing),
+		"offset":  offset,
+		"limit":   limit,
+		"markets": out,
+	})
+}
+
+func (s *Server) handleMarket(w http.ResponseWriter, r *http.Request) {
+	if err := s.refresh(r.Context()); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	id := r.PathValue("id")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, ok := s.byID[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("market %q not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.enrich(&s.markets[i]))
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if err := s.refresh(r.Context()); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	q := r.URL.Query()
+	limit, err := intParam(q.Get("limit"), defaultRankLimit, 1, maxRankLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("limit: %w", err))
+		return
+	}
+	window := api.Window24h
+	if v := q.Get("window"); v != "" {
+		idx, ok := parseName(api.WindowNames, v)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown window %q (available: %s)", v, strings.Join(api.WindowNames, ", ")))
+			return
+		}
+		window = api.Window(idx)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ms := s.markets
+	rank := func(list []types.Market, value func(m *types.Market) float64) []RankEntry {
+		out := make([]RankEntry, len(list))
+		for i := range list {
+			m := &list[i]
+			yes, _ := api.ParseOdds(m)
+			out[i] = RankEntry{ID: m.ID, Slug: m.GetSlug(), Question: m.Question, Yes: yes / 100, Value: value(m)}
+		}
+		return out
+	}
+	now := time.Now()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"updated": s.updated,
+		"window":  api.WindowNames[window],
+		"stats":   s.stats,
+		"rankings": map[string][]RankEntry{
+			"volume":       rank(api.TopByVolume(ms, limit), func(m *types.Market) float64 { return m.GetVolume() }),
+			"windowVolume": rank(api.TopByWindowVolume(ms, window, limit), func(m *types.Market) float64 { return api.WindowVolume(m, window) }),
+			"movers":       rank(api.TopMovers(ms, window, limit), func(m *types.Market) float64 { return api.PriceChange(m, window) }),
+			"momentum":     rank(api.TopMomentum(ms, limit), func(m *types.Market) float64 { return m.GetMomentumScore() }),
+			"engagement":   rank(api.MostEngaged(ms, limit), func(m *types.Market) float64 { return m.GetEngagementScore() }),
+			"spreads":      rank(api.TightestSpreads(ms, limit), func(m *types.Market) float64 { return m.GetSpread() }),
+			"openInterest": rank(api.HighestOpenInterest(ms, limit), func(m *types.Market) float64 { return m.OpenInterest }),
+			"closingSoon": rank(api.ClosingSoon(ms, now, limit), func(m *types.Market) float64 {
+				end, _ := time.Parse(time.RFC3339, m.EndDate)
+				return end.Sub(now).Hours()
+			}),
+			"unusual": rank(s.unusual(limit), func(m *types.Market) float64 { return s.signals[m.ID].Score() }),
+		},
+	})
+}
+
+func (s *Server) unusual(limit int) []types.Market {
+	var out []types.Market
+	for i := range s.markets {
+		if s.signals[s.markets[i].ID].Unusual() {
+			out = append(out, s.markets[i])
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return s.signals[out[i].ID].Score() > s.signals[out[j].ID].Score()
+	})
+	return out[:min(limit, len(out))]
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	history := s.store.History(id)
+	if len(history) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no history for market %q", id))
+		return
+	}
+	points := make([]HistoryPoint, len(history))
+	for i, p := range history {
+		points[i] = HistoryPoint{At: p.At, Point: p}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "points": points})
+}
+
+var nameReplacer = strings.NewReplacer(" ", "", "-", "", "_", "")
+
+func parseName(names []string, s string) (int, bool) {
+	s = nameReplacer.Replace(s)
+	for i, name := range names {
+		if strings.EqualFold(nameReplacer.Replace(name), s) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func intParam(s string, def, lo, hi int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < lo || (hi >= 0 && n > hi) {
+		if hi >= 0 {
+			return 0, fmt.Errorf("must be a number between %d and %d", lo, hi)
+		}
+		return 0, fmt.Errorf("must be a number of at least %d", lo)
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
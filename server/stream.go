@@ -0,0 +1,317 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"polyterm/diff"
+	"polyterm/query"
+	"polyterm/types"
+)
+
+const (
+	MaxSubscribers    = 256
+	subscriberBuffer  = 8
+	heartbeatInterval = 15 * time.Second
+	writeTimeout      = 10 * time.Second
+)
+
+const (
+	EventSnapshot = "snapshot"
+	EventResync   = "resync"
+	EventDiff     = "diff"
+	EventError    = "error"
+)
+
+var errTooManySubscribers = errors.New("too many subscribers")
+
+type Filter struct {
+	IDs        []string
+	Categories []string
+	Expr       query.Expr
+}
+
+type filterRequest struct {
+	IDs        []string `json:"ids"`
+	Categories []string `json:"categories"`
+	Filter     string   `json:"filter"`
+}
+
+func ParseFilter(ids, categories []string, expr string) (Filter, error) {
+	f := Filter{IDs: splitList(ids), Categories: splitList(categories)}
+	if strings.TrimSpace(expr) != "" {
+		e, err := query.Parse(expr)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.Expr = e
+	}
+	return f, nil
+}
+
+func (f Filter) Match(m *types.Market) bool {
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, m.ID) {
+		return false
+	}
+	if len(f.Categories) > 0 && !slices.ContainsFunc(f.Categories, func(c string) bool {
+		return strings.EqualFold(c, m.GetCategory())
+	}) {
+		return false
+	}
+	return f.Expr == nil || f.Expr.Match(m)
+}
+
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+type Event struct {
+	Type    string        `json:"type"`
+	Seq     uint64        `json:"seq"`
+	At      time.Time     `json:"at,omitzero"`
+	Markets []Market      `json:"markets,omitempty"`
+	Added   []Market      `json:"added,omitempty"`
+	Removed []string      `json:"removed,omitempty"`
+	Changed []diff.Change `json:"changed,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+type subscriber struct {
+	mu      sync.Mutex
+	filter  Filter
+	events  chan Event
+	filters chan Filter
+	lagged  atomic.Bool
+}
+
+func (sub *subscriber) currentFilter() Filter {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.filter
+}
+
+func (s *Server) subscribe(f Filter) (*subscriber, error) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	if len(s.subs) >= MaxSubscribers {
+		return nil, errTooManySubscribers
+	}
+	sub := &subscriber{filter: f, events: make(chan Event, subscriberBuffer), filters: make(chan Filter, 1)}
+	s.subs[sub] = struct{}{}
+	s.log.Debug("subscriber connected", "subscribers", len(s.subs))
+	return sub, nil
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	delete(s.subs, sub)
+	s.log.Debug("subscriber disconnected", "subscribers", len(s.subs))
+}
+
+func (s *Server) subscribers() int {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	return len(s.subs)
+}
+
+func (s *Server) publish(prev []types.Market, prevByID map[string]int) {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	if len(s.subs) == 0 {
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r := diff.Compare(prev, s.markets)
+	if r.Empty() {
+		return
+	}
+	for sub := range s.subs {
+		ev := s.diffEvent(sub.currentFilter(), r, prev, prevByID)
+		if len(ev.Added) == 0 && len(ev.Removed) == 0 && len(ev.Changed) == 0 {
+			continue
+		}
+		select {
+		case sub.events <- ev:
+		default:
+			sub.lagged.Store(true)
+		}
+	}
+}
+
+func (s *Server) diffEvent(f Filter, r diff.Result, prev []types.Market, prevByID map[string]int) Event {
+	ev := Event{Type: EventDiff, Seq: s.seq, At: s.updated}
+	for i := range r.Added {
+		if f.Match(&r.Added[i]) {
+			ev.Added = append(ev.Added, s.enrich(&r.Added[i]))
+		}
+	}
+	for i := range r.Removed {
+		if f.Match(&r.Removed[i]) {
+			ev.Removed = append(ev.Removed, r.Removed[i].ID)
+		}
+	}
+	for _, c := range r.Changed {
+		m := &s.markets[s.byID[c.ID]]
+		was, is := f.Match(&prev[prevByID[c.ID]]), f.Match(m)
+		switch {
+		case was && is:
+			ev.Changed = append(ev.Changed, c)
+		case is:
+			ev.Added = append(ev.Added, s.enrich(m))
+		case was:
+			ev.Removed = append(ev.Removed, c.ID)
+		}
+	}
+	return ev
+}
+
+func (s *Server) snapshotEvent(f Filter, typ string) Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ev := Event{Type: typ, Seq: s.seq, At: s.updated, Markets: []Market{}}
+	for i := range s.markets {
+		if f.Match(&s.markets[i]) {
+			ev.Markets = append(ev.Markets, s.enrich(&s.markets[i]))
+		}
+	}
+	return ev
+}
+
+func (s *Server) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.TTL / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			close(s.done)
+			return
+		case <-ticker.C:
+			if s.subscribers() > 0 {
+				s.refresh(ctx)
+			}
+		}
+	}
+}
+
+func (s *Server) streamContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+func (s *Server) stream(ctx context.Context, sub *subscriber, send func(Event) error, ping func() error) error {
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	if err := send(s.snapshotEvent(sub.currentFilter(), EventSnapshot)); err != nil {
+		return err
+	}
+	for {
+		var ev Event
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if err := ping(); err != nil {
+				return err
+			}
+			continue
+		case f := <-sub.filters:
+			sub.mu.Lock()
+			sub.filter = f
+			sub.mu.Unlock()
+			ev = s.snapshotEvent(f, EventSnapshot)
+		case ev = <-sub.events:
+			if sub.lagged.Swap(false) {
+				for len(sub.events) > 0 {
+					<-sub.events
+				}
+				s.log.Warn("slow subscriber, resyncing", "dropped_after_seq", ev.Seq)
+				ev = s.snapshotEvent(sub.currentFilter(), EventResync)
+			}
+		}
+		if err := send(ev); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) streamFilter(w http.ResponseWriter, r *http.Request) (*subscriber, bool) {
+	q := r.URL.Query()
+	f, err := ParseFilter(q["ids"], q["category"], q.Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("filter: %w", err))
+		return nil, false
+	}
+	if err := s.refresh(r.Context()); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return nil, false
+	}
+	sub, err := s.subscribe(f)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return nil, false
+	}
+	return sub, true
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.streamFilter(w, r)
+	if !ok {
+		return
+	}
+	defer s.unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(format string, args ...any) error {
+		rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+	send := func(ev Event) error {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		return write("id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Type, data)
+	}
+	ping := func() error { return write(": ping\n\n") }
+
+	ctx, cancel := s.streamContext(r.Context())
+	defer cancel()
+	if err := s.stream(ctx, sub, send, ping); err != nil {
+		s.log.Debug("event stream closed", "err", err)
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/coder/websocket"
+)
+
+const maxMessageSize = 64 << 10
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.streamFilter(w, r)
+	if !ok {
+		return
+	}
+	defer s.unsubscribe(sub)
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: s.opts.Origins})
+	if err != nil {
+		s.log.Debug("websocket handshake rejected", "err", err, "origin", r.Header.Get("Origin"))
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(maxMessageSize)
+
+	ctx, cancel := s.streamContext(r.Context())
+	defer cancel()
+
+	go func() {
+		defer cancel()
+		err := readSubscriptions(ctx, conn, sub)
+		if websocket.CloseStatus(err) == -1 && ctx.Err() == nil {
+			s.log.Debug("websocket read failed", "err", err)
+		}
+	}()
+
+	send := func(ev Event) error {
+		return writeEvent(ctx, conn, ev)
+	}
+	ping := func() error {
+		ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+		defer cancel()
+		return conn.Ping(ctx)
+	}
+	if err := s.stream(ctx, sub, send, ping); err != nil {
+		s.log.Debug("websocket stream closed", "err", err)
+		return
+	}
+	conn.Close(websocket.StatusGoingAway, "")
+}
+
+func readSubscriptions(ctx context.Context, conn *websocket.Conn, sub *subscriber) error {
+	for {
+		typ, msg, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if typ != websocket.MessageText {
+			continue
+		}
+
+		var req filterRequest
+		if err := json.Unmarshal(msg, &req); err != nil {
+			writeEvent(ctx, conn, Event{Type: EventError, Error: fmt.Sprintf("invalid subscription: %v", err)})
+			continue
+		}
+		f, err := ParseFilter(req.IDs, req.Categories, req.Filter)
+		if err != nil {
+			writeEvent(ctx, conn, Event{Type: EventError, Error: fmt.Sprintf("filter: %v", err)})
+			continue
+		}
+		select {
+		case <-sub.filters:
+		default:
+		}
+		sub.filters <- f
+	}
+}
+
+func writeEvent(ctx context.Context, conn *websocket.Conn, ev Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+	if err := conn.Write(ctx, websocket.MessageText, data); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package session
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polyterm/types"
+)
+
+const (
+	KindFetch     = "fetch"
+	KindArbitrage = "arbitrage"
+)
+
+type Frame struct {
+	At        time.Time          `json:"t"`
+	Kind      string             `json:"kind"`
+	Markets   []types.Market     `json:"markets,omitempty"`
+	Stats     *types.GlobalStats `json:"stats,omitempty"`
+	Arbitrage []types.ArbGroup   `json:"arbitrage,omitempty"`
+	Err       string             `json:"err,omitempty"`
+}
+
+func NewFrame(at time.Time, msg any) (Frame, bool) {
+	f := Frame{At: at}
+	var err error
+	switch msg := msg.(type) {
+	case types.FetchResult:
+		f.Kind, f.Markets, err = KindFetch, msg.Markets, msg.Err
+		stats := msg.Stats
+		f.Stats = &stats
+	case types.ArbitrageResult:
+		f.Kind, f.Arbitrage, err = KindArbitrage, msg.Groups, msg.Err
+	default:
+		return f, false
+	}
+	if err != nil {
+		f.Err = err.Error()
+	}
+	return f, true
+}
+
+func (f Frame) Msg() any {
+	var err error
+	if f.Err != "" {
+		err = errors.New(f.Err)
+	}
+	switch f.Kind {
+	case KindFetch:
+		msg := types.FetchResult{Markets: f.Markets, Err: err}
+		if f.Stats != nil {
+			msg.Stats = *f.Stats
+		}
+		return msg
+	case KindArbitrage:
+		return types.ArbitrageResult{Groups: f.Arbitrage, Err: err}
+	}
+	return nil
+}
+
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+	enc  *json.Encoder
+	n    int
+}
+
+func Create(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating recording: %w", err)
+	}
+	r := &Recorder{file: file}
+	var w io.Writer = file
+	if strings.HasSuffix(path, ".gz") {
+		r.gz = gzip.NewWriter(file)
+		w = r.gz
+	}
+	r.buf = bufio.NewWriter(w)
+	r.enc = json.NewEncoder(r.buf)
+	return r, nil
+}
+
+func (r *Recorder) Record(at time.Time, msg any) error {
+	f, ok := NewFrame(at, msg)
+	if !ok {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(f); err != nil {
+		return fmt.Errorf("recording frame: %w", err)
+	}
+	if err := r.buf.Flush(); err != nil {
+		return fmt.Errorf("recording frame: %w", err)
+	}
+	if r.gz != nil {
+		if err := r.gz.Flush(); err != nil {
+			return fmt.Errorf("recording frame: %w", err)
+		}
+	}
+	r.n++
+	return nil
+}
+
+func (r *Recorder) Frames() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.n
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	errs := []error{r.buf.Flush()}
+	if r.gz != nil {
+		errs = append(errs, r.gz.Close())
+	}
+	errs = append(errs, r.file.Close())
+	return errors.Join(errs...)
+}
+
+func Load(path string) ([]Frame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rd io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		defer gz.Close()
+		rd = gz
+	}
+
+	var frames []Frame
+	dec := json.NewDecoder(rd)
+	for {
+		var f Frame
+		err := dec.Decode(&f)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) && len(frames) > 0 {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: frame %d: %w", path, len(frames)+1, err)
+		}
+		if f.Msg() != nil {
+			frames = append(frames, f)
+		}
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("%s contains no recorded frames", path)
+	}
+	sort.SliceStable(frames, func(i, j int) bool { return frames[i].At.Before(frames[j].At) })
+	return frames, nil
+}
@@ -0,0 +1,379 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"polyterm/api"
+	"polyterm/types"
+)
+
+const (
+	resolutionsFile = "resolutions.json"
+	seenFile        = "seen.json"
+	MaxForecasts    = 200
+	forecastStep    = time.Hour
+	checkBackoff    = time.Hour
+	maxCheckBackoff = 7 * 24 * time.Hour
+)
+
+type Forecast struct {
+	At  time.Time `json:"t"`
+	Yes float64   `json:"yes"`
+}
+
+type seenMarket struct {
+	Last    time.Time `json:"last"`
+	End     time.Time `json:"end,omitempty"`
+	Checked time.Time `json:"checked,omitempty"`
+	Checks  int       `json:"checks,omitempty"`
+}
+
+// due reports whether a resolution check is worth making at now. Every
+// unsuccessful check doubles the wait before the next one, up to a week.
+func (m seenMarket) due(now time.Time) bool {
+	if m.Checks == 0 {
+		return true
+	}
+	wait := maxCheckBackoff
+	if m.Checks < 16 {
+		wait = min(maxCheckBackoff, checkBackoff<<(m.Checks-1))
+	}
+	return !now.Before(m.Checked.Add(wait))
+}
+
+type Resolution struct {
+	ID        string     `json:"id"`
+	Question  string     `json:"question"`
+	Category  string     `json:"category"`
+	Outcome   string     `json:"outcome"`
+	Yes       bool       `json:"yes"`
+	ClosedAt  time.Time  `json:"closedAt"`
+	Volume    float64    `json:"volume"`
+	Forecasts []Forecast `json:"forecasts"`
+}
+
+func (s *Store) loadResolutions() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, resolutionsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var list []Resolution
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	for _, r := range list {
+		s.resolutions[r.ID] = r
+	}
+	return nil
+}
+
+func (s *Store) saveResolutions() error {
+	list := make([]Resolution, 0, len(s.resolutions))
+	for _, r := range s.resolutions {
+		list = append(list, r)
+	}
+	sortResolutions(list)
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, resolutionsFile)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func sortResolutions(list []Resolution) {
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].ClosedAt.Equal(list[j].ClosedAt) {
+			return list[i].ClosedAt.Before(list[j].ClosedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+}
+
+func (s *Store) Resolutions() []Resolution {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Resolution, 0, len(s.resolutions))
+	for _, r := range s.resolutions {
+		list = append(list, r)
+	}
+	sortResolutions(list)
+	return list
+}
+
+func (s *Store) loadSeen() error {
+	data, err := os.ReadFile(filepath.Join(s.dir, seenFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return s.rebuildSeen()
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.seen)
+}
+
+// rebuildSeen indexes every market in the on-disk history. It only runs
+// once, for stores written before the index existed.
+func (s *Store) rebuildSeen() error {
+	collect := func(snap Snapshot) error {
+		for _, p := range snap.Points {
+			m := s.seen[p.ID]
+			if snap.At.After(m.Last) {
+				m.Last = snap.At
+			}
+			s.seen[p.ID] = m
+		}
+		return nil
+	}
+	all := func(time.Time) bool { return true }
+
+	if err := scanSnapshots(filepath.Join(s.dir, longTermFile), all, collect); err != nil {
+		return err
+	}
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		if err := scanSnapshots(filepath.Join(s.dir, name), all, collect); err != nil {
+			return err
+		}
+	}
+	return s.saveSeen()
+}
+
+func (s *Store) saveSeen() error {
+	data, err := json.Marshal(s.seen)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, seenFile)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	s.seenDirty = false
+	return nil
+}
+
+func (s *Store) see(snap Snapshot, markets []types.Market) {
+	for i := range markets {
+		m := s.seen[markets[i].ID]
+		m.Last, m.End = snap.At, api.CloseTime(&markets[i]).UTC()
+		s.seen[markets[i].ID] = m
+	}
+	s.seenDirty = true
+}
+
+// Unresolved returns the tracked markets worth checking for a resolution:
+// those past their end date and those that have dropped out of the latest
+// listing, once their backoff since the last check has elapsed.
+func (s *Store) Unresolved(now time.Time) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest time.Time
+	for _, m := range s.seen {
+		if m.Last.After(latest) {
+			latest = m.Last
+		}
+	}
+	var ids []string
+	for id, m := range s.seen {
+		if _, ok := s.resolutions[id]; ok {
+			continue
+		}
+		if (m.Last.Before(latest) || (!m.End.IsZero() && m.End.Before(now))) && m.due(now) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Checked records a resolution check of ids at at, pushing back the next
+// check of any that are still unresolved.
+func (s *Store) Checked(ids []string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		m, ok := s.seen[id]
+		if !ok {
+			continue
+		}
+		m.Checked = at
+		m.Checks++
+		s.seen[id] = m
+	}
+	return s.saveSeen()
+}
+
+func (s *Store) Resolve(markets []types.Market) ([]Resolution, error) {
+	closing := make(map[string]time.Time)
+	s.mu.RLock()
+	for i := range markets {
+		m := &markets[i]
+		if _, ok := s.resolutions[m.ID]; ok {
+			continue
+		}
+		if _, ok := api.Winner(m); ok {
+			closing[m.ID] = api.CloseTime(m)
+		}
+	}
+	s.mu.RUnlock()
+	if len(closing) == 0 {
+		return nil, nil
+	}
+
+	history, err := s.forecasts(closing)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var added []Resolution
+	for i := range markets {
+		m := &markets[i]
+		closedAt, ok := closing[m.ID]
+		if !ok {
+			continue
+		}
+		if _, ok := s.resolutions[m.ID]; ok {
+			continue
+		}
+		forecasts := history[m.ID]
+		for _, p := range s.history[m.ID] {
+			if (len(forecasts) == 0 || p.At.After(forecasts[len(forecasts)-1].At)) && (closedAt.IsZero() || p.At.Before(closedAt)) {
+				forecasts = append(forecasts, Forecast{At: p.At, Yes: p.Yes})
+			}
+		}
+		if len(forecasts) == 0 {
+			continue
+		}
+		r, _ := NewResolution(m)
+		if r.ClosedAt.IsZero() {
+			r.ClosedAt = forecasts[len(forecasts)-1].At.UTC()
+		}
+		r.Forecasts = thin(forecasts, MaxForecasts)
+		s.resolutions[m.ID] = r
+		delete(s.seen, m.ID)
+		added = append(added, r)
+	}
+
+	if len(added) == 0 {
+		return nil, nil
+	}
+	if err := s.saveResolutions(); err != nil {
+		return nil, err
+	}
+	return added, s.saveSeen()
+}
+
+func NewResolution(m *types.Market) (Resolution, bool) {
+	winner, ok := api.Winner(m)
+	if !ok {
+		return Resolution{}, false
+	}
+	outcome := "Yes"
+	if winner == 1 {
+		outcome = "No"
+	}
+	if outcomes := m.GetOutcomes(); winner < len(outcomes) {
+		outcome = outcomes[winner]
+	}
+	return Resolution{
+		ID:       m.ID,
+		Question: m.Question,
+		Category: m.GetCategory(),
+		Outcome:  outcome,
+		Yes:      winner == 0,
+		ClosedAt: api.CloseTime(m).UTC(),
+		Volume:   m.GetVolume(),
+	}, true
+}
+
+// forecasts collects the pre-close price history of every market in closing
+// in a single pass over the stored snapshots.
+func (s *Store) forecasts(closing map[string]time.Time) (map[string][]Forecast, error) {
+	out := make(map[string][]Forecast, len(closing))
+	var last time.Time
+	for _, closedAt := range closing {
+		if closedAt.IsZero() {
+			last = time.Time{}
+			break
+		}
+		if closedAt.After(last) {
+			last = closedAt
+		}
+	}
+	collect := func(snap Snapshot) error {
+		for _, p := range snap.Points {
+			if closedAt, ok := closing[p.ID]; ok && (closedAt.IsZero() || snap.At.Before(closedAt)) {
+				out[p.ID] = append(out[p.ID], Forecast{At: snap.At, Yes: p.Yes})
+			}
+		}
+		return nil
+	}
+
+	var next time.Time
+	keep := func(at time.Time) bool {
+		if at.Before(next) {
+			return false
+		}
+		next = at.Add(forecastStep)
+		return true
+	}
+	if err := scanSnapshots(filepath.Join(s.dir, longTermFile), keep, collect); err != nil {
+		return nil, err
+	}
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range files {
+		day, err := time.Parse(fileLayout, strings.TrimSuffix(name, ".jsonl"))
+		if err == nil && !last.IsZero() && day.After(last) {
+			break
+		}
+		if err := scanSnapshots(filepath.Join(s.dir, name), keep, collect); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func thin(forecasts []Forecast, n int) []Forecast {
+	if len(forecasts) <= n {
+		return forecasts
+	}
+	first := forecasts[0].At
+	span := forecasts[len(forecasts)-1].At.Sub(first)
+	out := make([]Forecast, 0, n)
+	slot := -1
+	for _, f := range forecasts {
+		if k := int(float64(n-1) * float64(f.At.Sub(first)) / float64(span)); k != slot {
+			out = append(out, f)
+			slot = k
+		}
+	}
+	return out
+}
@@ -0,0 +1,483 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"polyterm/api"
+	"polyterm/config"
+	"polyterm/types"
+)
+
+const (
+	DefaultRetention = 30 * 24 * time.Hour
+	MaxPoints        = 1000
+	RecordInterval   = 5 * time.Minute
+	LongRetention    = 2 * 365 * 24 * time.Hour
+	fileLayout       = "2006-01-02"
+	longTermFile     = "longterm.jsonl"
+)
+
+type Point struct {
+	At           time.Time `json:"-"`
+	ID           string    `json:"id"`
+	Yes          float64   `json:"yes"`
+	BestBid      float64   `json:"bid,omitempty"`
+	BestAsk      float64   `json:"ask,omitempty"`
+	LastPrice    float64   `json:"last,omitempty"`
+	Volume       float64   `json:"vol,omitempty"`
+	Volume24h    float64   `json:"vol24h,omitempty"`
+	Liquidity    float64   `json:"liq,omitempty"`
+	OpenInterest float64   `json:"oi,omitempty"`
+	Change1h     float64   `json:"chg1h,omitempty"`
+	Change24h    float64   `json:"chg24h,omitempty"`
+	Category     string    `json:"cat,omitempty"`
+}
+
+type Snapshot struct {
+	At     time.Time `json:"t"`
+	Points []Point   `json:"markets"`
+}
+
+type Store struct {
+	mu          sync.RWMutex
+	dir         string
+	retention   time.Duration
+	history     map[string][]Point
+	resolutions map[string]Resolution
+	seen        map[string]seenMarket
+	seenDirty   bool
+	lastWrite   time.Time
+	pending     *Snapshot
+}
+
+func Dir() string {
+	return filepath.Join(config.Dir(), "snapshots")
+}
+
+func Open(dir string, retention time.Duration) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &Store{
+		dir:         dir,
+		retention:   retention,
+		history:     make(map[string][]Point),
+		resolutions: make(map[string]Resolution),
+		seen:        make(map[string]seenMarket),
+	}
+	if err := s.loadResolutions(); err != nil {
+		return nil, err
+	}
+	if err := s.loadSeen(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadRecent(time.Now().Add(-retention)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) loadRecent(from time.Time) error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+
+	type part struct {
+		path string
+		skip int
+	}
+	var parts []part
+	for i, need := len(files)-1, MaxPoints; i >= 0 && need > 0; i-- {
+		day, err := time.Parse(fileLayout, strings.TrimSuffix(files[i], ".jsonl"))
+		if err != nil {
+			continue
+		}
+		if day.Add(24 * time.Hour).Before(from) {
+			break
+		}
+		path := filepath.Join(s.dir, files[i])
+		n, err := countLines(path)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, part{path, max(0, n-need)})
+		need -= n
+	}
+
+	for i := len(parts) - 1; i >= 0; i-- {
+		err := readFile(parts[i].path, parts[i].skip, from, time.Time{}, func(snap Snapshot) error {
+			s.add(snap)
+			s.lastWrite = snap.At
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func countLines(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	n := 0
+	buf := make([]byte, 1<<16)
+	for {
+		k, err := f.Read(buf)
+		n += bytes.Count(buf[:k], []byte{'\n'})
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func NewPoint(m *types.Market) Point {
+	yes, _ := api.ParseOdds(m)
+	return Point{
+		ID:           m.ID,
+		Yes:          yes / 100,
+		BestBid:      m.BestBid,
+		BestAsk:      m.BestAsk,
+		LastPrice:    m.LastTradePrice,
+		Volume:       m.GetVolume(),
+		Volume24h:    m.Volume24hr,
+		Liquidity:    m.GetLiquidity(),
+		OpenInterest: m.OpenInterest,
+		Change1h:     m.OneHourPriceChange,
+		Change24h:    m.OneDayPriceChange,
+		Category:     m.GetCategory(),
+	}
+}
+
+func (p Point) Market() types.Market {
+	return types.Market{
+		ID:                 p.ID,
+		Active:             true,
+		OutcomesStr:        `["Yes","No"]`,
+		OutcomePricesStr:   fmt.Sprintf(`["%g","%g"]`, p.Yes, 1-p.Yes),
+		BestBid:            p.BestBid,
+		BestAsk:            p.BestAsk,
+		LastTradePrice:     p.LastPrice,
+		VolumeNum:          p.Volume,
+		Volume24hr:         p.Volume24h,
+		LiquidityNum:       p.Liquidity,
+		OpenInterest:       p.OpenInterest,
+		OneHourPriceChange: p.Change1h,
+		OneDayPriceChange:  p.Change24h,
+		Category:           p.Category,
+	}
+}
+
+func (s *Store) Record(at time.Time, markets []types.Market) (bool, error) {
+	snap := Snapshot{At: at.UTC(), Points: make([]Point, len(markets))}
+	for i := range markets {
+		snap.Points[i] = NewPoint(&markets[i])
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(snap)
+	s.see(snap, markets)
+	if snap.At.Sub(s.lastWrite) < RecordInterval {
+		s.pending = &snap
+		return false, nil
+	}
+	if err := s.write(snap); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Store) Flush() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil || !s.pending.At.After(s.lastWrite) {
+		return false, nil
+	}
+	if err := s.write(*s.pending); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Store) write(snap Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, snap.At.Format(fileLayout)+".jsonl")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.lastWrite = snap.At
+	s.pending = nil
+	if s.seenDirty {
+		return s.saveSeen()
+	}
+	return nil
+}
+
+func (s *Store) add(snap Snapshot) {
+	for _, p := range snap.Points {
+		p.At = snap.At
+		h := append(s.history[p.ID], p)
+		if len(h) > MaxPoints {
+			h = h[len(h)-MaxPoints:]
+		}
+		s.history[p.ID] = h
+	}
+}
+
+func (s *Store) History(id string) []Point {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.history[id]
+}
+
+func (s *Store) Markets() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.history))
+	for id := range s.history {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *Store) Snapshots(from, to time.Time, fn func(Snapshot) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.each(from, to, fn)
+}
+
+func (s *Store) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := now.Add(-s.retention).UTC().Format(fileLayout)
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	var daily [][]byte
+	for _, name := range files {
+		if strings.TrimSuffix(name, ".jsonl") >= cutoff {
+			continue
+		}
+		path := filepath.Join(s.dir, name)
+		last, err := lastLine(path)
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			daily = append(daily, last)
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if err := s.appendLongTerm(daily, now.Add(-LongRetention)); err != nil {
+		return err
+	}
+
+	for id, m := range s.seen {
+		if m.Last.Before(now.Add(-LongRetention)) {
+			delete(s.seen, id)
+			s.seenDirty = true
+		}
+	}
+	if s.seenDirty {
+		return s.saveSeen()
+	}
+	return nil
+}
+
+func lastLine(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var last []byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1<<20), 64<<20)
+	for scanner.Scan() {
+		if _, ok := lineTime(scanner.Bytes()); ok {
+			last = append(last[:0], scanner.Bytes()...)
+		}
+	}
+	return last, scanner.Err()
+}
+
+func (s *Store) appendLongTerm(lines [][]byte, cutoff time.Time) error {
+	path := filepath.Join(s.dir, longTermFile)
+	var kept [][]byte
+	err := scanLines(path, func(line []byte) error {
+		if at, ok := lineTime(line); ok && at.Before(cutoff) {
+			return nil
+		}
+		kept = append(kept, append([]byte(nil), line...))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 && len(kept) == 0 {
+		return nil
+	}
+
+	var b bytes.Buffer
+	for _, line := range append(kept, lines...) {
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(path+".tmp", b.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (s *Store) files() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		if _, err := time.Parse(fileLayout, strings.TrimSuffix(e.Name(), ".jsonl")); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// each calls fn for every stored snapshot in [from, to]. Days that have
+// already been pruned are read from the long-term file, one snapshot per day.
+func (s *Store) each(from, to time.Time, fn func(Snapshot) error) error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+
+	until := to
+	if len(files) > 0 {
+		first, err := time.Parse(fileLayout, strings.TrimSuffix(files[0], ".jsonl"))
+		if err == nil && (until.IsZero() || first.Before(until)) {
+			until = first.Add(-time.Nanosecond)
+		}
+	}
+	if from.IsZero() || until.IsZero() || from.Before(until) {
+		if err := readFile(filepath.Join(s.dir, longTermFile), 0, from, until, fn); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range files {
+		day, err := time.Parse(fileLayout, strings.TrimSuffix(name, ".jsonl"))
+		if err != nil {
+			continue
+		}
+		if !from.IsZero() && day.Add(24*time.Hour).Before(from) {
+			continue
+		}
+		if !to.IsZero() && day.After(to) {
+			break
+		}
+		if err := readFile(filepath.Join(s.dir, name), 0, from, to, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readFile(path string, skip int, from, to time.Time, fn func(Snapshot) error) error {
+	line := 0
+	return scanSnapshots(path, func(at time.Time) bool {
+		line++
+		return line > skip && (from.IsZero() || !at.Before(from)) && (to.IsZero() || !at.After(to))
+	}, fn)
+}
+
+func scanSnapshots(path string, keep func(time.Time) bool, fn func(Snapshot) error) error {
+	return scanLines(path, func(line []byte) error {
+		at, ok := lineTime(line)
+		if !ok || !keep(at) {
+			return nil
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(line, &snap); err != nil {
+			return nil
+		}
+		return fn(snap)
+	})
+}
+
+func scanLines(path string, fn func([]byte) error) error {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1<<20), 64<<20)
+	for scanner.Scan() {
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func lineTime(line []byte) (time.Time, bool) {
+	rest, ok := bytes.CutPrefix(line, []byte(`{"t":"`))
+	if !ok {
+		return time.Time{}, false
+	}
+	end := bytes.IndexByte(rest, '"')
+	if end < 0 {
+		return time.Time{}, false
+	}
+	at, err := time.Parse(time.RFC3339Nano, string(rest[:end]))
+	return at, err == nil
+}
@@ -9,6 +9,7 @@ type Market struct {
 	ID                  string  `json:"id"`
 	Question            string  `json:"question"`
 	Description         string  `json:"description"`
+	ResolutionSource    string  `json:"resolutionSource"`
 	VolumeStr           string  `json:"volume"`
 	Volume24hr          float64 `json:"volume24hr"`
 	LiquidityStr        string  `json:"liquidity"`
@@ -18,9 +19,11 @@ type Market struct {
 	Active              bool    `json:"active"`
 	Closed              bool    `json:"closed"`
 	MarketSlug          string  `json:"marketSlug"`
+	Slug                string  `json:"slug"`
 	OutcomesStr         string  `json:"outcomes"`
 	OutcomePricesStr    string  `json:"outcomePrices"`
 	CloseTime           string  `json:"closeTime"`
+	ClosedTime          string  `json:"closedTime"`
 	Category            string  `json:"category"`
 	LastTradePrice      float64 `json:"lastTradePrice"`
 	OneDayPriceChange   float64 `json:"oneDayPriceChange"`
@@ -40,6 +43,25 @@ type Market struct {
 	OpenInterest        float64 `json:"openInterest"`
 	Featured            bool    `json:"featured"`
 	Competitive         float64 `json:"competitive"`
+	NegRisk             bool    `json:"negRisk"`
+	ClobTokenIDsStr     string  `json:"clobTokenIds"`
+	Tags                []Tag   `json:"tags"`
+	Events              []Event `json:"events,omitempty"`
+}
+
+type Event struct {
+	ID               string   `json:"id"`
+	Slug             string   `json:"slug"`
+	Title            string   `json:"title"`
+	NegRisk          bool     `json:"negRisk"`
+	NegRiskAugmented bool     `json:"negRiskAugmented"`
+	Markets          []Market `json:"markets"`
+}
+
+type Tag struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Slug  string `json:"slug"`
 }
 
 func (m *Market) GetVolume() float64 {
@@ -116,6 +138,34 @@ func (m *Market) GetOutcomePrices() []string {
 	return prices
 }
 
+func (m *Market) GetSlug() string {
+	if m.Slug != "" {
+		return m.Slug
+	}
+	return m.MarketSlug
+}
+
+func (m *Market) EventSlug() string {
+	for _, e := range m.Events {
+		if e.Slug != "" {
+			return e.Slug
+		}
+	}
+	return ""
+}
+
+func (m *Market) GetCategory() string {
+	if m.Category != "" {
+		return m.Category
+	}
+	for _, t := range m.Tags {
+		if t.Label != "" {
+			return t.Label
+		}
+	}
+	return "Uncategorized"
+}
+
 func (m *Market) GetOutcomes() []string {
 	var outcomes []string
 	if m.OutcomesStr != "" {
@@ -124,6 +174,14 @@ func (m *Market) GetOutcomes() []string {
 	return outcomes
 }
 
+func (m *Market) GetClobTokenIDs() []string {
+	var ids []string
+	if m.ClobTokenIDsStr != "" {
+		json.Unmarshal([]byte(m.ClobTokenIDsStr), &ids)
+	}
+	return ids
+}
+
 type MarketsResponse struct {
 	Data    []Market `json:"data"`
 	Markets []Market `json:"markets"`
@@ -139,6 +197,18 @@ type GlobalStats struct {
 	TopGainer       *Market
 	TopGainerChange float64
 	TopVolume       *Market
+	Categories      []CategoryStats
+}
+
+type CategoryStats struct {
+	Category        string
+	Markets         int
+	Volume24h       float64
+	TotalVolume     float64
+	Liquidity       float64
+	OpenInterest    float64
+	MedianSpread    float64
+	AvgAbsChange24h float64
 }
 
 type FetchResult struct {
@@ -146,3 +216,50 @@ type FetchResult struct {
 	Stats   GlobalStats
 	Err     error
 }
+
+type BookLevel struct {
+	Price float64
+	Size  float64
+}
+
+type OrderBook struct {
+	Bids []BookLevel
+	Asks []BookLevel
+}
+
+type ArbLeg struct {
+	MarketID string
+	Question string
+	Outcome  string
+	TokenID  string
+	Ask      float64
+	Bid      float64
+	AskSize  float64
+	BidSize  float64
+}
+
+type ArbGroup struct {
+	Key      string
+	Title    string
+	Kind     string
+	Legs     []ArbLeg
+	SumAsk   float64
+	SumBid   float64
+	BuyEdge  float64
+	SellEdge float64
+	Side     string
+	Edge     float64
+	Fees     float64
+	MaxSize  float64
+	Depth    bool
+}
+
+type ClosedResult struct {
+	Markets []Market
+	Err     error
+}
+
+type ArbitrageResult struct {
+	Groups []ArbGroup
+	Err    error
+}
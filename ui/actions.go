@@ -0,0 +1,815 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"polyterm/alerts"
+	"polyterm/api"
+	"polyterm/config"
+	"polyterm/export"
+	"polyterm/types"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type actionScope int
+
+const (
+	scopeGlobal actionScope = iota
+	scopeList
+	scopeMarkets
+	scopeAnalytics
+	scopeCategories
+	scopeArbitrage
+	scopeResolved
+	scopeDetail
+	scopeMarket
+	scopeReplay
+)
+
+type action struct {
+	id     string
+	title  string
+	hidden bool
+	scope  actionScope
+	prompt func(m *Model) string
+	run    func(m *Model, arg string) tea.Cmd
+}
+
+var actions []action
+
+func init() {
+	actions = []action{
+		{id: "quit", title: "Quit", scope: scopeGlobal, run: actionQuit},
+		{id: "back", title: "Back / quit", hidden: true, scope: scopeGlobal, run: actionBack},
+		{id: "palette", title: "Open command palette", hidden: true, scope: scopeGlobal, run: actionOpenPalette},
+		{id: "help", title: "Show keybindings", scope: scopeGlobal, run: actionToggleHelp},
+		{id: "refresh", title: "Refresh markets", scope: scopeList, run: actionRefresh},
+		{id: "autorefresh", title: "Toggle auto-refresh", scope: scopeGlobal, run: actionToggleAutoRefresh},
+		{id: "page.next", title: "Next page", scope: scopeList, run: actionNextPage},
+		{id: "page.markets", title: "Go to Markets page", scope: scopeList, run: actionPage(pageMarkets)},
+		{id: "page.analytics", title: "Go to Analytics page", scope: scopeList, run: actionPage(pageStats)},
+		{id: "page.categories", title: "Go to Categories page", scope: scopeList, run: actionPage(pageCategories)},
+		{id: "page.arbitrage", title: "Go to Arbitrage page", scope: scopeList, run: actionPage(pageArbitrage)},
+		{id: "page.resolved", title: "Go to Resolved page", scope: scopeList, run: actionPage(pageResolved)},
+		{id: "search.start", title: "Start interactive search", scope: scopeMarkets, run: actionStartSearch},
+		{id: "search.set", title: "Search markets…", scope: scopeMarkets, prompt: staticPrompt("Search query"), run: actionSetSearch},
+		{id: "filter.cycle", title: "Cycle filter", scope: scopeMarkets, run: actionCycleFilter},
+		{id: "filter.set", title: "Set filter…", scope: scopeMarkets, prompt: namesPrompt("Filter", api.FilterNames), run: actionSetFilter},
+		{id: "sort.cycle", title: "Cycle sort", scope: scopeMarkets, run: actionCycleSort},
+		{id: "sort.set", title: "Set sort…", scope: scopeMarkets, prompt: namesPrompt("Sort", api.SortNames), run: actionSetSort},
+		{id: "clear", title: "Clear search and filters", scope: scopeMarkets, run: actionClear},
+		{id: "view.apply", title: "Apply view…", scope: scopeMarkets, prompt: viewsPrompt, run: actionApplyView},
+		{id: "view.save", title: "Save current view…", scope: scopeMarkets, prompt: staticPrompt("View name"), run: actionSaveView},
+		{id: "select.toggle", title: "Toggle market selection", scope: scopeMarkets, run: actionToggleMark},
+		{id: "select.visual", title: "Start / finish visual selection", scope: scopeMarkets, run: actionToggleVisual},
+		{id: "select.up", title: "Extend selection up", hidden: true, scope: scopeMarkets, run: actionExtendSelection(-1)},
+		{id: "select.down", title: "Extend selection down", hidden: true, scope: scopeMarkets, run: actionExtendSelection(1)},
+		{id: "select.clear", title: "Clear selection", scope: scopeMarkets, run: actionClearSelection},
+		{id: "watch.toggle", title: "Add / remove selected markets from watchlist", scope: scopeMarkets, run: actionToggleWatch},
+		{id: "copy.ids", title: "Copy selected market IDs", scope: scopeMarkets, run: actionCopy("ID")},
+		{id: "copy.slugs", title: "Copy selected market slugs", scope: scopeMarkets, run: actionCopy("slug")},
+		{id: "compare", title: "Compare selected markets side by side", scope: scopeMarkets, run: actionCompare},
+		{id: "layout.split", title: "Toggle split detail panel", scope: scopeMarkets, run: actionToggleSplit},
+		{id: "export", title: "Export results or selection…", scope: scopeMarkets, prompt: staticPrompt("File (.csv or .json, empty for default)"), run: actionExport},
+		{id: "theme.set", title: "Switch theme…", scope: scopeGlobal, prompt: themesPrompt, run: actionSetTheme},
+		{id: "alert.add", title: "Add alert on selected markets…", scope: scopeGlobal, prompt: staticPrompt("Condition, e.g. above 60 or change24h below -10"), run: actionAddAlert},
+		{id: "market.jump", title: "Jump to market by ID…", scope: scopeGlobal, prompt: staticPrompt("Market ID"), run: actionJumpToMarket},
+		{id: "market.browser", title: "Open market on polymarket.com", scope: scopeMarket, run: actionOpenBrowser},
+		{id: "detail.open", title: "Open market details", scope: scopeList, run: actionOpenDetail},
+		{id: "nav.up", title: "Move up", hidden: true, scope: scopeList, run: actionMoveUp},
+		{id: "nav.down", title: "Move down", hidden: true, scope: scopeList, run: actionMoveDown},
+		{id: "nav.top", title: "Jump to top", hidden: true, scope: scopeMarkets, run: actionTop},
+		{id: "nav.bottom", title: "Jump to bottom", hidden: true, scope: scopeMarkets, run: actionBottom},
+		{id: "nav.pageup", title: "Page up", hidden: true, scope: scopeList, run: actionPageUp},
+		{id: "nav.pagedown", title: "Page down", hidden: true, scope: scopeList, run: actionPageDown},
+		{id: "panel.next", title: "Focus next panel", hidden: true, scope: scopeAnalytics, run: actionNextPanel},
+		{id: "panel.prev", title: "Focus previous panel", hidden: true, scope: scopeAnalytics, run: actionPrevPanel},
+		{id: "panel.window", title: "Cycle panel time window", scope: scopeAnalytics, run: actionCycleWindow},
+		{id: "category.sort", title: "Cycle category sort", scope: scopeCategories, run: actionCycleCategorySort},
+		{id: "category.view", title: "Toggle category table/treemap", scope: scopeCategories, run: actionToggleTreemap},
+		{id: "arb.all", title: "Toggle all groups / opportunities only", scope: scopeArbitrage, run: actionToggleArbAll},
+		{id: "resolved.search", title: "Search closed markets", scope: scopeResolved, run: actionStartSearch},
+		{id: "resolved.range", title: "Cycle closed date range", scope: scopeResolved, run: actionCycleResolvedRange},
+		{id: "resolved.range.set", title: "Set closed date range…", scope: scopeResolved, prompt: staticPrompt("YYYY-MM-DD..YYYY-MM-DD, a single day, or e.g. 14d"), run: actionSetResolvedRange},
+		{id: "detail.up", title: "Scroll description up", hidden: true, scope: scopeDetail, run: actionScrollDescription(-1)},
+		{id: "detail.down", title: "Scroll description down", hidden: true, scope: scopeDetail, run: actionScrollDescription(1)},
+		{id: "detail.pageup", title: "Page description up", hidden: true, scope: scopeDetail, run: actionPageDescription(-1)},
+		{id: "detail.pagedown", title: "Page description down", hidden: true, scope: scopeDetail, run: actionPageDescription(1)},
+		{id: "detail.search", title: "Search the description", scope: scopeDetail, run: actionStartSearch},
+		{id: "detail.next", title: "Next description match", scope: scopeDetail, run: actionDescriptionMatch(1)},
+		{id: "detail.prev", title: "Previous description match", scope: scopeDetail, run: actionDescriptionMatch(-1)},
+		{id: "replay.toggle", title: "Play / pause replay", scope: scopeReplay, run: actionToggleReplay},
+		{id: "replay.faster", title: "Faster replay", scope: scopeReplay, run: actionReplaySpeed(1)},
+		{id: "replay.slower", title: "Slower replay", scope: scopeReplay, run: actionReplaySpeed(-1)},
+		{id: "replay.prev", title: "Previous recorded frame", scope: scopeReplay, run: actionReplayStep(-1)},
+		{id: "replay.next", title: "Next recorded frame", scope: scopeReplay, run: actionReplayStep(1)},
+		{id: "replay.back", title: "Seek back 5 minutes", scope: scopeReplay, run: actionReplaySeekBy(-replaySeekStep)},
+		{id: "replay.forward", title: "Seek forward 5 minutes", scope: scopeReplay, run: actionReplaySeekBy(replaySeekStep)},
+		{id: "replay.seek", title: "Seek replay to…", scope: scopeReplay, prompt: staticPrompt("HH:MM[:SS], YYYY-MM-DD HH:MM, +5m/-30s or 50%"), run: actionReplaySeek},
+	}
+}
+
+func findAction(id string) *action {
+	for i := range actions {
+		if actions[i].id == id {
+			return &actions[i]
+		}
+	}
+	return nil
+}
+
+func (m *Model) actionForKey(msg tea.KeyMsg) *action {
+	for i := range actions {
+		a := &actions[i]
+		if m.inScope(a.scope) && key.Matches(msg, m.keys.binding(a.id)) {
+			return a
+		}
+	}
+	return nil
+}
+
+func (m *Model) inScope(scope actionScope) bool {
+	switch scope {
+	case scopeList:
+		return m.currentView == viewList
+	case scopeMarkets:
+		return m.currentView == viewList && m.currentPage == pageMarkets
+	case scopeAnalytics:
+		return m.currentView == viewList && m.currentPage == pageStats
+	case scopeCategories:
+		return m.currentView == viewList && m.currentPage == pageCategories
+	case scopeArbitrage:
+		return m.currentView == viewList && m.currentPage == pageArbitrage
+	case scopeResolved:
+		return m.currentView == viewList && m.currentPage == pageResolved
+	case scopeDetail:
+		return m.currentView == viewDetail
+	case scopeMarket:
+		return m.currentView == viewDetail || (m.currentView == viewList && m.currentPage == pageMarkets)
+	case scopeReplay:
+		return m.replaying()
+	}
+	return true
+}
+
+func (m *Model) enterScope(scope actionScope) {
+	switch scope {
+	case scopeList:
+		m.currentView = viewList
+		m.selectedID = ""
+	case scopeMarkets:
+		m.currentView = viewList
+		m.selectedID = ""
+		m.currentPage = pageMarkets
+	case scopeMarket:
+		if !m.inScope(scope) {
+			m.currentView = viewList
+			m.selectedID = ""
+			m.currentPage = pageMarkets
+		}
+	case scopeAnalytics:
+		m.currentView = viewList
+		m.selectedID = ""
+		m.currentPage = pageStats
+	case scopeCategories:
+		m.currentView = viewList
+		m.selectedID = ""
+		m.currentPage = pageCategories
+	case scopeArbitrage:
+		m.currentView = viewList
+		m.selectedID = ""
+		m.currentPage = pageArbitrage
+	case scopeResolved:
+		m.currentView = viewList
+		m.selectedID = ""
+		m.currentPage = pageResolved
+	}
+}
+
+func (m *Model) setStatus(format string, args ...interface{}) {
+	m.status = fmt.Sprintf(format, args...)
+	m.statusErr = false
+}
+
+func (m *Model) setError(err error) {
+	m.status = err.Error()
+	m.statusErr = true
+}
+
+func staticPrompt(label string) func(m *Model) string {
+	return func(m *Model) string {
+		return label
+	}
+}
+
+func namesPrompt(label string, names []string) func(m *Model) string {
+	return func(m *Model) string {
+		return fmt.Sprintf("%s (%s)", label, strings.ToLower(strings.Join(names, ", ")))
+	}
+}
+
+func viewsPrompt(m *Model) string {
+	if len(m.cfg.Views) == 0 {
+		return "View name (none saved yet)"
+	}
+	names := make([]string, len(m.cfg.Views))
+	for i, v := range m.cfg.Views {
+		names[i] = v.Name
+	}
+	return fmt.Sprintf("View (%s)", strings.Join(names, ", "))
+}
+
+func themesPrompt(m *Model) string {
+	return fmt.Sprintf("Theme (%s), current: %s", strings.Join(themeNames(m.cfg.Themes), ", "), currentTheme.Name)
+}
+
+func parseName(names []string, s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	for i, name := range names {
+		if strings.EqualFold(name, s) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (m Model) displayMarkets() []types.Market {
+	if len(m.filteredMarkets) == 0 {
+		return m.markets
+	}
+	return m.filteredMarkets
+}
+
+func marketIndex(markets []types.Market, id string) int {
+	for i := range markets {
+		if markets[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Model) currentMarket() *types.Market {
+	if m.currentView == viewDetail && m.selectedID != "" {
+		return &m.selected
+	}
+	displayMarkets := m.displayMarkets()
+	if m.cursor < 0 || m.cursor >= len(displayMarkets) {
+		return nil
+	}
+	return &displayMarkets[m.cursor]
+}
+
+func (m *Model) cursorID() string {
+	if m.currentView == viewDetail && m.selectedID != "" {
+		return m.selectedID
+	}
+	if market := m.currentMarket(); market != nil {
+		return market.ID
+	}
+	return ""
+}
+
+func (m *Model) followCursor(id string) {
+	n := len(m.displayMarkets())
+	maxScroll := max(0, n-m.maxDisplay)
+	if i := marketIndex(m.displayMarkets(), id); id != "" && i >= 0 {
+		offset := m.cursor - m.scroll
+		m.cursor = i
+		m.scroll = max(0, min(maxScroll, i-offset))
+		return
+	}
+	m.cursor = max(0, min(m.cursor, n-1))
+	m.scroll = max(0, min(m.scroll, maxScroll))
+}
+
+func (m *Model) pinSelected() {
+	if m.selectedID == "" {
+		return
+	}
+	if i := marketIndex(m.markets, m.selectedID); i >= 0 {
+		m.selected = m.markets[i]
+	}
+	m.syncDescription()
+}
+
+func (m *Model) selectMarket(market *types.Market) {
+	m.selectedID = market.ID
+	m.selected = *market
+	m.currentView = viewDetail
+	m.detailQuery = ""
+	m.syncDescription()
+}
+
+func (m *Model) resetListPosition() {
+	m.applyFiltersAndSort()
+	m.cursor = 0
+	m.scroll = 0
+}
+
+func actionQuit(m *Model, _ string) tea.Cmd {
+	return tea.Quit
+}
+
+func actionBack(m *Model, _ string) tea.Cmd {
+	if m.currentView == viewDetail && m.detailQuery != "" {
+		m.detailQuery = ""
+		m.syncDescription()
+		return nil
+	}
+	if m.currentView != viewList {
+		m.currentView = viewList
+		m.selectedID = ""
+		m.compare = nil
+		return nil
+	}
+	if m.currentPage == pageMarkets && m.clearSelection() {
+		return nil
+	}
+	return tea.Quit
+}
+
+func actionOpenPalette(m *Model, _ string) tea.Cmd {
+	m.openPalette()
+	return nil
+}
+
+func actionToggleHelp(m *Model, _ string) tea.Cmd {
+	m.showHelp = !m.showHelp
+	return nil
+}
+
+func actionRefresh(m *Model, _ string) tea.Cmd {
+	if m.currentPage == pageResolved {
+		return m.loadResolved()
+	}
+	if m.replaying() {
+		m.setStatus("Refresh is disabled during replay")
+		return nil
+	}
+	m.loading = true
+	m.err = nil
+	return tea.Batch(m.spinner.Tick, fetchMarketsCmd(m.cache, 500))
+}
+
+func actionToggleAutoRefresh(m *Model, _ string) tea.Cmd {
+	m.autoRefresh = !m.autoRefresh
+	return nil
+}
+
+func actionNextPage(m *Model, _ string) tea.Cmd {
+	for i, tab := range pageTabs {
+		if tab.page == m.currentPage {
+			return m.setPage(pageTabs[(i+1)%len(pageTabs)].page)
+		}
+	}
+	return nil
+}
+
+func actionPage(page pageMode) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		return m.setPage(page)
+	}
+}
+
+func (m *Model) setPage(page pageMode) tea.Cmd {
+	m.currentPage = page
+	if page == pageResolved && m.resolved == nil {
+		return m.loadResolved()
+	}
+	return nil
+}
+
+func (m *Model) searchTarget() *string {
+	if m.currentView == viewDetail {
+		return &m.detailQuery
+	}
+	if m.currentPage == pageResolved {
+		return &m.resolvedQuery
+	}
+	return &m.searchQuery
+}
+
+func (m *Model) applySearch() {
+	if m.currentView == viewDetail {
+		m.syncDescription()
+		m.showMatch(0)
+		return
+	}
+	if m.currentPage == pageResolved {
+		m.resolvedCursor = 0
+		m.applyResolvedFilter()
+		return
+	}
+	m.applyFiltersAndSort()
+}
+
+func actionStartSearch(m *Model, _ string) tea.Cmd {
+	m.searchMode = true
+	return nil
+}
+
+func actionSetSearch(m *Model, arg string) tea.Cmd {
+	m.searchQuery = strings.TrimSpace(arg)
+	m.resetListPosition()
+	return nil
+}
+
+func actionCycleFilter(m *Model, _ string) tea.Cmd {
+	m.filterBy = (m.filterBy + 1) % 5
+	m.applyFiltersAndSort()
+	return nil
+}
+
+func actionSetFilter(m *Model, arg string) tea.Cmd {
+	idx, ok := parseName(api.FilterNames, arg)
+	if !ok {
+		m.setError(fmt.Errorf("unknown filter %q", arg))
+		return nil
+	}
+	m.filterBy = api.FilterMode(idx)
+	m.applyFiltersAndSort()
+	return nil
+}
+
+func actionCycleSort(m *Model, _ string) tea.Cmd {
+	m.sortBy = (m.sortBy + 1) % api.SortMode(len(api.SortNames))
+	m.applyFiltersAndSort()
+	return nil
+}
+
+func actionSetSort(m *Model, arg string) tea.Cmd {
+	idx, ok := parseName(api.SortNames, arg)
+	if !ok {
+		m.setError(fmt.Errorf("unknown sort %q", arg))
+		return nil
+	}
+	m.sortBy = api.SortMode(idx)
+	m.applyFiltersAndSort()
+	return nil
+}
+
+func actionClear(m *Model, _ string) tea.Cmd {
+	m.searchQuery = ""
+	m.filterBy = api.FilterAll
+	m.categoryFilter = ""
+	m.sortBy = api.SortVolume
+	m.resetListPosition()
+	return nil
+}
+
+func actionApplyView(m *Model, arg string) tea.Cmd {
+	v, ok := m.cfg.View(strings.TrimSpace(arg))
+	if !ok {
+		m.setError(fmt.Errorf("no saved view named %q", arg))
+		return nil
+	}
+
+	m.searchQuery = v.Search
+	m.filterBy = api.FilterAll
+	if idx, ok := parseName(api.FilterNames, v.Filter); ok {
+		m.filterBy = api.FilterMode(idx)
+	}
+	m.sortBy = api.SortVolume
+	if idx, ok := parseName(api.SortNames, v.Sort); ok {
+		m.sortBy = api.SortMode(idx)
+	}
+	m.resetListPosition()
+	m.setStatus("Applied view %q", v.Name)
+	return nil
+}
+
+func actionSaveView(m *Model, arg string) tea.Cmd {
+	name := strings.TrimSpace(arg)
+	if name == "" {
+		m.setError(fmt.Errorf("view name is required"))
+		return nil
+	}
+
+	m.cfg.SaveView(config.View{
+		Name:   name,
+		Search: m.searchQuery,
+		Filter: api.FilterNames[m.filterBy],
+		Sort:   api.SortNames[m.sortBy],
+	})
+	if err := config.Save(m.cfg); err != nil {
+		m.setError(err)
+		return nil
+	}
+	m.setStatus("Saved view %q", name)
+	return nil
+}
+
+func actionExport(m *Model, arg string) tea.Cmd {
+	markets, name := m.filteredMarkets, "polyterm"
+	if marked := m.markedMarkets(); len(marked) > 0 {
+		markets, name = marked, "polyterm-selection"
+	}
+	path := strings.TrimSpace(arg)
+	if path == "" {
+		path = fmt.Sprintf("%s-%s.csv", name, time.Now().Format("20060102-150405"))
+	}
+	if err := export.ToFile(path, markets); err != nil {
+		m.setError(err)
+		return nil
+	}
+	m.setStatus("Exported %d markets to %s", len(markets), path)
+	return nil
+}
+
+func actionSetTheme(m *Model, arg string) tea.Cmd {
+	name := strings.TrimSpace(arg)
+	theme, err := resolveTheme(name, m.cfg.Themes)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+	applyTheme(theme)
+
+	m.cfg.Theme = name
+	if err := config.Save(m.cfg); err != nil {
+		m.setError(err)
+		return nil
+	}
+	m.setStatus("Theme set to %s", theme.Name)
+	return nil
+}
+
+func actionAddAlert(m *Model, arg string) tea.Cmd {
+	targets := m.targetMarkets()
+	if len(targets) == 0 {
+		m.setError(fmt.Errorf("no market selected"))
+		return nil
+	}
+
+	metric, op, threshold, err := alerts.ParseCondition(arg)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+
+	var rule alerts.Rule
+	for i := range targets {
+		rule = alerts.Rule{
+			MarketID:  targets[i].ID,
+			Label:     truncate(targets[i].Question, 40),
+			Metric:    metric,
+			Op:        op,
+			Threshold: threshold,
+		}
+		m.alerts.Add(rule)
+	}
+	m.cfg.Alerts = m.alerts.Rules()
+	if err := config.Save(m.cfg); err != nil {
+		m.setError(err)
+		return nil
+	}
+	if len(targets) > 1 {
+		m.setStatus("Added %d alerts: %s %s %g", len(targets), metric, op, threshold)
+		m.clearSelection()
+		return nil
+	}
+	m.setStatus("Added alert: %s", rule)
+	return nil
+}
+
+func actionJumpToMarket(m *Model, arg string) tea.Cmd {
+	id := strings.TrimSpace(arg)
+	if !m.openMarket(id) {
+		m.setError(fmt.Errorf("market %q not found", id))
+	}
+	return nil
+}
+
+func (m *Model) openMarket(id string) bool {
+	i := marketIndex(m.filteredMarkets, id)
+	if i < 0 {
+		m.searchQuery = ""
+		m.filterBy = api.FilterAll
+		m.applyFiltersAndSort()
+		i = marketIndex(m.filteredMarkets, id)
+	}
+	if i < 0 {
+		return false
+	}
+
+	m.cursor = i
+	m.scroll = max(0, i-m.maxDisplay/2)
+	m.selectMarket(&m.filteredMarkets[i])
+	return true
+}
+
+func actionOpenDetail(m *Model, _ string) tea.Cmd {
+	switch m.currentPage {
+	case pageStats:
+		m.openPanelEntry()
+		return nil
+	case pageArbitrage:
+		m.openArbGroup()
+		return nil
+	case pageCategories:
+		m.openCategory()
+		return nil
+	case pageResolved:
+		if len(m.resolvedView) > 0 {
+			m.selectMarket(&m.resolved[m.resolvedView[m.resolvedCursor]])
+		}
+		return nil
+	}
+	if len(m.filteredMarkets) > 0 && m.cursor < len(m.filteredMarkets) {
+		m.selectMarket(&m.filteredMarkets[m.cursor])
+	}
+	return nil
+}
+
+func (m *Model) listLen() int {
+	return len(m.displayMarkets())
+}
+
+func actionNextPanel(m *Model, _ string) tea.Cmd {
+	m.movePanelFocus(1)
+	return nil
+}
+
+func actionPrevPanel(m *Model, _ string) tea.Cmd {
+	m.movePanelFocus(-1)
+	return nil
+}
+
+func actionCycleWindow(m *Model, _ string) tea.Cmd {
+	m.cyclePanelWindow()
+	return nil
+}
+
+func actionCycleCategorySort(m *Model, _ string) tea.Cmd {
+	m.categorySort = (m.categorySort + 1) % categorySort(len(categorySortNames))
+	m.categoryCursor = 0
+	m.categoryScroll = 0
+	return nil
+}
+
+func actionToggleTreemap(m *Model, _ string) tea.Cmd {
+	m.categoryTreemap = !m.categoryTreemap
+	return nil
+}
+
+func actionCycleResolvedRange(m *Model, _ string) tea.Cmd {
+	m.resolvedRange = (m.resolvedRange + 1) % len(resolvedRanges)
+	m.resolvedCursor = 0
+	m.applyResolvedFilter()
+	return nil
+}
+
+func actionSetResolvedRange(m *Model, arg string) tea.Cmd {
+	from, to, err := parseRange(arg)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+	m.resolvedRange = -1
+	m.resolvedFrom, m.resolvedTo = from, to
+	m.resolvedCursor = 0
+	m.applyResolvedFilter()
+	return nil
+}
+
+func actionToggleArbAll(m *Model, _ string) tea.Cmd {
+	m.arbAll = !m.arbAll
+	m.arbCursor = 0
+	m.arbScroll = 0
+	return nil
+}
+
+func actionMoveUp(m *Model, _ string) tea.Cmd {
+	switch m.currentPage {
+	case pageStats:
+		m.movePanelCursor(-1)
+		return nil
+	case pageCategories:
+		m.moveCategoryCursor(-1)
+		return nil
+	case pageArbitrage:
+		m.moveArbCursor(-1)
+		return nil
+	case pageResolved:
+		m.moveResolvedCursor(-1)
+		return nil
+	}
+	if m.cursor > 0 {
+		m.cursor--
+
+		middle := m.maxDisplay / 2
+		if m.cursor < m.scroll+middle && m.scroll > 0 {
+			m.scroll--
+		}
+	}
+	return nil
+}
+
+func actionMoveDown(m *Model, _ string) tea.Cmd {
+	switch m.currentPage {
+	case pageStats:
+		m.movePanelCursor(1)
+		return nil
+	case pageCategories:
+		m.moveCategoryCursor(1)
+		return nil
+	case pageArbitrage:
+		m.moveArbCursor(1)
+		return nil
+	case pageResolved:
+		m.moveResolvedCursor(1)
+		return nil
+	}
+	maxLen := m.listLen()
+	if m.cursor < maxLen-1 {
+		m.cursor++
+
+		middle := m.maxDisplay / 2
+		maxScroll := maxLen - m.maxDisplay
+		if maxScroll < 0 {
+			maxScroll = 0
+		}
+
+		if m.cursor >= m.scroll+middle && m.scroll < maxScroll {
+			m.scroll++
+		}
+	}
+	return nil
+}
+
+func actionTop(m *Model, _ string) tea.Cmd {
+	m.cursor = 0
+	m.scroll = 0
+	return nil
+}
+
+func actionBottom(m *Model, _ string) tea.Cmd {
+	maxLen := m.listLen()
+	m.cursor = max(0, maxLen-1)
+	maxScroll := maxLen - m.maxDisplay
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	m.scroll = maxScroll
+	return nil
+}
+
+func actionPageUp(m *Model, _ string) tea.Cmd {
+	switch m.currentPage {
+	case pageStats:
+		m.movePanelCursor(-panelMinHeight)
+		return nil
+	case pageCategories:
+		m.moveCategoryCursor(-m.categoriesHeight())
+		return nil
+	case pageArbitrage:
+		m.moveArbCursor(-m.arbitrageRows())
+		return nil
+	case pageResolved:
+		m.moveResolvedCursor(-m.resolvedRows())
+		return nil
+	}
+	m.cursor -= m.maxDisplay
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.scroll -= m.maxDisplay
+	if m.scroll < 0 {
+		m.scroll = 0
+	}
+	return nil
+}
+
+func actionPageDown(m *Model, _ string) tea.Cmd {
+	switch m.currentPage {
+	case pageStats:
+		m.movePanelCursor(panelMinHeight)
+		return nil
+	case pageCategories:
+		m.moveCategoryCursor(m.categoriesHeight())
+		return nil
+	case pageArbitrage:
+		m.moveArbCursor(m.arbitrageRows())
+		return nil
+	case pageResolved:
+		m.moveResolvedCursor(m.resolvedRows())
+		return nil
+	}
+	maxLen := m.listLen()
+	m.cursor = max(0, min(m.cursor+m.maxDisplay, maxLen-1))
+
+	maxScroll := maxLen - m.maxDisplay
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	m.scroll += m.maxDisplay
+	if m.scroll > maxScroll {
+		m.scroll = maxScroll
+	}
+	return nil
+}
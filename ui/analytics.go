@@ -0,0 +1,350 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"polyterm/api"
+	"polyterm/metrics"
+	"polyterm/query"
+	"polyterm/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	panelRankLimit  = 50
+	panelMinWidth   = 56
+	panelMinHeight  = 8
+	panelMaxColumns = 3
+)
+
+type analyticsPanel struct {
+	title   string
+	windows []api.Window
+	window  int
+	limit   int
+	width   int
+	filter  query.Expr
+	metric  query.Metric
+	rank    rankFunc
+	value   valueFunc
+	cursor  int
+	scroll  int
+}
+
+type panelRow struct {
+	panels []int
+	height int
+}
+
+func (p *analyticsPanel) currentWindow() api.Window {
+	if len(p.windows) == 0 {
+		return api.Window24h
+	}
+	return p.windows[p.window%len(p.windows)]
+}
+
+func (m Model) panelEntries(p *analyticsPanel) (widgetInput, []types.Market) {
+	in := widgetInput{markets: m.markets, signals: m.signals, window: p.currentWindow(), metric: p.metric, listed: m.changes.listed, watchlist: m.cfg.Watchlist}
+	if p.filter != nil {
+		in.markets = make([]types.Market, 0, len(m.markets))
+		for i := range m.markets {
+			if p.filter.Match(&m.markets[i]) {
+				in.markets = append(in.markets, m.markets[i])
+			}
+		}
+	}
+	return in, p.rank(in, p.limit)
+}
+
+func (m *Model) focusedPanel() *analyticsPanel {
+	if m.panelFocus < 0 || m.panelFocus >= len(m.panels) {
+		return nil
+	}
+	return &m.panels[m.panelFocus]
+}
+
+func (m Model) dashboardRows() []panelRow {
+	if len(m.panelRows) > 0 {
+		return m.panelRows
+	}
+
+	cols := max(1, min(panelMaxColumns, m.width/panelMinWidth))
+	var rows []panelRow
+	for i := 0; i < len(m.panels); i += cols {
+		var row panelRow
+		for j := i; j < min(i+cols, len(m.panels)); j++ {
+			row.panels = append(row.panels, j)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m Model) rowHeights(rows []panelRow) []int {
+	avail := m.analyticsHeight()
+	auto := 0
+	for _, row := range rows {
+		if row.height == 0 {
+			auto++
+		} else {
+			avail -= row.height
+		}
+	}
+
+	autoHeight := panelMinHeight
+	if auto > 0 {
+		visible := max(1, min(auto, avail/panelMinHeight))
+		autoHeight = max(panelMinHeight, avail/visible)
+	}
+
+	heights := make([]int, len(rows))
+	for i, row := range rows {
+		heights[i] = row.height
+		if heights[i] == 0 {
+			heights[i] = autoHeight
+		}
+	}
+	return heights
+}
+
+func (m Model) panelWidths(row panelRow) []int {
+	total := 0
+	for _, i := range row.panels {
+		total += m.panels[i].width
+	}
+
+	avail := max(panelMinWidth, m.width-1)
+	widths := make([]int, len(row.panels))
+	used := 0
+	for j, i := range row.panels {
+		widths[j] = avail * m.panels[i].width / total
+		used += widths[j]
+	}
+	widths[len(widths)-1] += avail - used
+	return widths
+}
+
+func (m Model) panelHeight(panel int) int {
+	rows := m.dashboardRows()
+	return m.rowHeights(rows)[m.panelRowOf(panel, rows)]
+}
+
+func (m Model) panelRowOf(panel int, rows []panelRow) int {
+	for r, row := range rows {
+		for _, i := range row.panels {
+			if i == panel {
+				return r
+			}
+		}
+	}
+	return 0
+}
+
+func (m Model) analyticsHeight() int {
+	chrome := 9
+	if m.status != "" {
+		chrome++
+	}
+	return max(panelMinHeight, m.height-chrome)
+}
+
+func (p *analyticsPanel) visibleRows(panelHeight int) int {
+	return max(1, panelHeight-4)
+}
+
+func (m *Model) movePanelCursor(delta int) {
+	p := m.focusedPanel()
+	if p == nil {
+		return
+	}
+	_, entries := m.panelEntries(p)
+	n := len(entries)
+	if n == 0 {
+		return
+	}
+
+	p.cursor = max(0, min(n-1, p.cursor+delta))
+
+	rows := p.visibleRows(m.panelHeight(m.panelFocus))
+	if p.cursor < p.scroll {
+		p.scroll = p.cursor
+	}
+	if p.cursor >= p.scroll+rows {
+		p.scroll = p.cursor - rows + 1
+	}
+}
+
+func (m *Model) movePanelFocus(delta int) {
+	if len(m.panels) == 0 {
+		return
+	}
+	m.panelFocus = (m.panelFocus + delta + len(m.panels)) % len(m.panels)
+}
+
+func (m *Model) cyclePanelWindow() {
+	p := m.focusedPanel()
+	if p == nil {
+		return
+	}
+	if len(p.windows) < 2 {
+		m.setStatus("%s has no time window", strings.ToLower(p.title))
+		return
+	}
+	p.window = (p.window + 1) % len(p.windows)
+	p.cursor = 0
+	p.scroll = 0
+}
+
+func (m *Model) openPanelEntry() {
+	p := m.focusedPanel()
+	if p == nil {
+		return
+	}
+	_, entries := m.panelEntries(p)
+	if p.cursor >= len(entries) {
+		return
+	}
+	if !m.openMarket(entries[p.cursor].ID) {
+		m.setError(fmt.Errorf("%q is below the minimum volume shown in the market list", truncate(entries[p.cursor].Question, 40)))
+	}
+}
+
+func (m Model) renderAnalytics() string {
+	if len(m.markets) == 0 {
+		return MutedStyle.Render("No data available")
+	}
+
+	labelStyle := lipgloss.NewStyle().Foreground(colorDim)
+	valueStyle := lipgloss.NewStyle().Foreground(colorBright).Bold(true)
+	overview := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		labelStyle.Render("Markets: "), valueStyle.Render(fmt.Sprintf("%d", m.stats.TotalMarkets)),
+		labelStyle.Render("  Active: "), valueStyle.Render(fmt.Sprintf("%d", m.stats.ActiveMarkets)),
+		labelStyle.Render("  24h Volume: "), valueStyle.Render(formatCurrency(m.stats.Volume24h)),
+		labelStyle.Render("  Total Volume: "), valueStyle.Render(formatCurrency(m.stats.TotalVolume)),
+		labelStyle.Render("  Avg Liquidity: "), valueStyle.Render(formatCurrency(m.stats.AvgLiquidity)),
+	)
+
+	rows := m.dashboardRows()
+	heights := m.rowHeights(rows)
+	focusRow := m.panelRowOf(m.panelFocus, rows)
+
+	avail := m.analyticsHeight()
+	firstRow, used := focusRow, heights[focusRow]
+	for firstRow > 0 && used+heights[firstRow-1] <= avail {
+		firstRow--
+		used += heights[firstRow]
+	}
+	lastRow := focusRow
+	for lastRow+1 < len(rows) && used+heights[lastRow+1] <= avail {
+		lastRow++
+		used += heights[lastRow]
+	}
+
+	var gridRows []string
+	for r := firstRow; r <= lastRow; r++ {
+		widths := m.panelWidths(rows[r])
+		cells := make([]string, len(rows[r].panels))
+		for j, i := range rows[r].panels {
+			cells[j] = m.renderPanel(i, widths[j], heights[r])
+		}
+		gridRows = append(gridRows, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+
+	if firstRow > 0 || lastRow < len(rows)-1 {
+		overview += MutedStyle.Render(fmt.Sprintf("   panels row %d-%d of %d", firstRow+1, lastRow+1, len(rows)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, overview, "", lipgloss.JoinVertical(lipgloss.Left, gridRows...))
+}
+
+func (m Model) renderPanel(i, width, height int) string {
+	p := &m.panels[i]
+	focused := i == m.panelFocus
+	in, entries := m.panelEntries(p)
+	innerWidth := width - 4
+
+	title := p.title
+	if len(p.windows) > 0 {
+		title += " · " + api.WindowNames[p.currentWindow()]
+	}
+	titleStyle := lipgloss.NewStyle().Foreground(colorSubtle).Bold(true)
+	if focused {
+		titleStyle = titleStyle.Foreground(colorPrimary)
+	}
+
+	lines := []string{titleStyle.Render(truncate(title, innerWidth))}
+
+	rows := p.visibleRows(height)
+	end := min(p.scroll+rows, len(entries))
+	if len(entries) == 0 {
+		lines = append(lines, MutedStyle.Render("No data"))
+	}
+	for j := p.scroll; j < end; j++ {
+		market := &entries[j]
+		value, valueStyle := p.value(market, in)
+
+		questionWidth := max(10, innerWidth-lipgloss.Width(value)-6)
+		label := fmt.Sprintf("%2d. %-*s", j+1, questionWidth, truncate(market.Question, questionWidth))
+
+		line := lipgloss.NewStyle().Foreground(colorText).Render(label) + " " + valueStyle.Render(value)
+		if focused && j == p.cursor {
+			line = SelectedRowStyle.UnsetPadding().Width(innerWidth).Render(label + " " + value)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(entries) > rows {
+		lines = append(lines, MutedStyle.Render(fmt.Sprintf("%d-%d of %d", p.scroll+1, end, len(entries))))
+	}
+
+	border := colorMuted
+	if focused {
+		border = colorPrimary
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(0, 1).
+		Width(width - 2).
+		Height(height - 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+func getTopByMetric(markets []types.Market, metric query.Metric, ascending bool, limit int) []types.Market {
+	sorted := make([]types.Market, len(markets))
+	copy(sorted, markets)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if ascending {
+			return metric.Value(&sorted[i]) < metric.Value(&sorted[j])
+		}
+		return metric.Value(&sorted[i]) > metric.Value(&sorted[j])
+	})
+
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+func getUnusualActivity(markets []types.Market, signals map[string]metrics.Signals, limit int) []types.Market {
+	filtered := make([]types.Market, 0)
+	for _, m := range markets {
+		if signals[m.ID].Unusual() {
+			filtered = append(filtered, m)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return signals[filtered[i].ID].Score() > signals[filtered[j].ID].Score()
+	})
+
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered
+}
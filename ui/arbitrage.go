@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"polyterm/api"
+	"polyterm/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	arbGroupMinWidth = 20
+	arbLegLines      = 8
+)
+
+var arbColumns = []tableColumn{
+	{title: "Group"},
+	{title: "Type", width: 6},
+	{title: "Legs", width: 4},
+	{title: "Σ Ask", width: 6},
+	{title: "Σ Bid", width: 6},
+	{title: "Side", width: 8},
+	{title: "Edge ¢", width: 7},
+	{title: "Max Size", width: 9},
+	{title: "Profit", width: 9},
+}
+
+func scanArbitrageCmd(markets []types.Market, feeBps float64) tea.Cmd {
+	return func() tea.Msg {
+		groups, err := api.ScanArbitrage(context.Background(), markets, feeBps)
+		return types.ArbitrageResult{Groups: groups, Err: err}
+	}
+}
+
+func (m Model) isOpportunity(g *types.ArbGroup) bool {
+	return g.Edge*100 > m.cfg.Arbitrage.MinEdge
+}
+
+func (m Model) arbitrageGroups() []types.ArbGroup {
+	if m.arbAll {
+		return m.arbGroups
+	}
+	var groups []types.ArbGroup
+	for i := range m.arbGroups {
+		if m.isOpportunity(&m.arbGroups[i]) {
+			groups = append(groups, m.arbGroups[i])
+		}
+	}
+	return groups
+}
+
+func (m Model) arbitrageRows() int {
+	chrome := 11 + arbLegLines
+	if m.status != "" {
+		chrome++
+	}
+	return max(3, m.height-chrome)
+}
+
+func (m *Model) moveArbCursor(delta int) {
+	n := len(m.arbitrageGroups())
+	if n == 0 {
+		return
+	}
+	m.arbCursor = max(0, min(n-1, m.arbCursor+delta))
+
+	rows := m.arbitrageRows()
+	if m.arbCursor < m.arbScroll {
+		m.arbScroll = m.arbCursor
+	}
+	if m.arbCursor >= m.arbScroll+rows {
+		m.arbScroll = m.arbCursor - rows + 1
+	}
+}
+
+func (m *Model) openArbGroup() {
+	groups := m.arbitrageGroups()
+	if m.arbCursor >= len(groups) {
+		return
+	}
+	g := &groups[m.arbCursor]
+	for _, leg := range g.Legs {
+		if m.openMarket(leg.MarketID) {
+			return
+		}
+	}
+	m.setError(fmt.Errorf("no market in %q is in the market list", truncate(g.Title, 40)))
+}
+
+func (m Model) renderArbitrage() string {
+	groups := m.arbitrageGroups()
+
+	opportunities := 0
+	for i := range m.arbGroups {
+		if m.isOpportunity(&m.arbGroups[i]) {
+			opportunities++
+		}
+	}
+	show := "Opportunities"
+	if m.arbAll {
+		show = "All groups"
+	}
+	bar := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		MutedStyle.Render("Show: "), lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render(show),
+		MutedStyle.Render(fmt.Sprintf("  Groups: %d  Opportunities: %d  Fee: %g bps  Min edge: %g¢", len(m.arbGroups), opportunities, m.cfg.Arbitrage.FeeBps, m.cfg.Arbitrage.MinEdge)),
+	)
+	if m.arbScanning {
+		bar += MutedStyle.Render("  scanning…")
+	}
+
+	lines := []string{bar, ""}
+	if m.arbErr != nil {
+		lines = append(lines, ErrorStyle.Render("Scan: "+truncate(m.arbErr.Error(), max(20, m.width-8))))
+	}
+	if len(groups) == 0 {
+		msg := "No riskless buy-all or sell-all after fees right now"
+		if len(m.arbGroups) == 0 {
+			msg = "No related market groups scanned yet"
+		}
+		return strings.Join(append(lines, MutedStyle.Render(msg)), "\n")
+	}
+
+	lines = append(lines, m.renderArbTable(groups))
+	if m.arbCursor < len(groups) {
+		lines = append(lines, "", m.renderArbLegs(&groups[m.arbCursor]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderArbTable(groups []types.ArbGroup) string {
+	widths := make([]int, len(arbColumns))
+	headers := make([]string, len(arbColumns))
+	used := 0
+	for i, col := range arbColumns {
+		widths[i] = col.width
+		headers[i] = col.title
+		used += col.width + 3
+	}
+	widths[0] = max(arbGroupMinWidth, m.width-used-3)
+
+	rows := []string{renderHeaderRow(headers, widths)}
+	visible := m.arbitrageRows()
+	end := min(len(groups), m.arbScroll+visible)
+	for i := m.arbScroll; i < end; i++ {
+		g := &groups[i]
+		size, profit := "n/a", "n/a"
+		if g.Depth {
+			size = fmt.Sprintf("%.0f", g.MaxSize)
+			profit = formatCurrency(max(0, g.Edge) * g.MaxSize)
+		}
+		cells := []string{
+			truncate(g.Title, widths[0]),
+			g.Kind,
+			fmt.Sprintf("%d", len(g.Legs)),
+			fmt.Sprintf("%.3f", g.SumAsk),
+			fmt.Sprintf("%.3f", g.SumBid),
+			g.Side,
+			fmt.Sprintf("%+.2f", g.Edge*100),
+			size,
+			profit,
+		}
+
+		style := TableCellStyle
+		if i == m.arbCursor {
+			style = SelectedRowStyle
+		} else if i%2 == 0 {
+			style = StripedRowStyle
+		}
+
+		formatted := make([]string, len(cells))
+		for j, cell := range cells {
+			cellStyle := style
+			if j == 6 && i != m.arbCursor {
+				cellStyle = style.Inherit(getPriceChangeStyle(g.Edge))
+			}
+			formatted[j] = cellStyle.Render(fmt.Sprintf("%-*s", widths[j], cell))
+		}
+		rows = append(rows, joinCells(formatted))
+	}
+
+	if len(groups) > visible {
+		rows = append(rows, MutedStyle.Render(fmt.Sprintf("Showing %d-%d of %d groups", m.arbScroll+1, end, len(groups))))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m Model) renderArbLegs(g *types.ArbGroup) string {
+	titleStyle := lipgloss.NewStyle().Foreground(colorSecondary).Bold(true)
+	action, price := "Buy", "ask"
+	if g.Side == api.ArbSellAll {
+		action, price = "Sell", "bid"
+	}
+
+	lines := []string{titleStyle.Render(fmt.Sprintf("%s %s of each leg  (fees %.2f¢ per set)", action, price, g.Fees*100))}
+	width := max(arbGroupMinWidth, m.width-48)
+	shown := min(len(g.Legs), arbLegLines-2)
+	for _, leg := range g.Legs[:shown] {
+		name := leg.Question
+		if g.Kind == api.ArbKindPair {
+			name = leg.Outcome
+		}
+		quote := fmt.Sprintf("ask %.3f  bid %.3f", leg.Ask, leg.Bid)
+		if g.Depth {
+			quote = fmt.Sprintf("ask %.3f x %-7.0f bid %.3f x %.0f", leg.Ask, leg.AskSize, leg.Bid, leg.BidSize)
+		}
+		lines = append(lines, fmt.Sprintf("  %-*s %s", width, truncate(name, width), MutedStyle.Render(quote)))
+	}
+	if len(g.Legs) > shown {
+		lines = append(lines, MutedStyle.Render(fmt.Sprintf("  … %d more legs", len(g.Legs)-shown)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,253 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"polyterm/backtest"
+	"polyterm/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	backtestChartHeight = 8
+	backtestChrome      = 18
+)
+
+var backtestColumns = []tableColumn{
+	{title: "Market"},
+	{title: "Side", width: 4},
+	{title: "Entry", width: 12},
+	{title: "Price", width: 6},
+	{title: "Exit", width: 12},
+	{title: "Price", width: 6},
+	{title: "PnL", width: 9},
+	{title: "Reason", width: 14},
+}
+
+type BacktestModel struct {
+	report backtest.Report
+	width  int
+	height int
+	scroll int
+}
+
+func NewBacktestModel(cfg config.Config, report backtest.Report) (BacktestModel, error) {
+	theme, err := resolveTheme(cfg.Theme, cfg.Themes)
+	if err != nil {
+		return BacktestModel{}, err
+	}
+	applyTheme(theme)
+	return BacktestModel{report: report, width: 100, height: 40}, nil
+}
+
+func (m BacktestModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m BacktestModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			m.scroll--
+		case "down", "j":
+			m.scroll++
+		case "pgup":
+			m.scroll -= m.tradeRows()
+		case "pgdown":
+			m.scroll += m.tradeRows()
+		case "g", "home":
+			m.scroll = 0
+		case "G", "end":
+			m.scroll = len(m.report.Trades)
+		}
+		m.scroll = max(0, min(m.scroll, len(m.report.Trades)-m.tradeRows()))
+	}
+	return m, nil
+}
+
+func (m BacktestModel) tradeRows() int {
+	return max(3, m.height-backtestChrome-backtestChartHeight)
+}
+
+func (m BacktestModel) View() string {
+	r := m.report
+	label := lipgloss.NewStyle().Foreground(colorMuted)
+	value := lipgloss.NewStyle().Foreground(colorPrimary).Bold(true)
+	stat := func(name, v string) string { return label.Render(name+" ") + value.Render(v) }
+
+	period := "no snapshots"
+	if r.Snapshots > 0 {
+		period = fmt.Sprintf("%s → %s", formatTime(r.From), formatTime(r.To))
+	}
+	open := len(r.Trades) - r.Closed
+
+	lines := []string{
+		"",
+		BrandStyle.Render("POLYTERM") + " " + HeaderStyle.Render("Backtest: "+r.Strategy),
+		"",
+		strings.Join([]string{
+			stat("Period", period),
+			stat("Snapshots", fmt.Sprintf("%d", r.Snapshots)),
+		}, "   "),
+		strings.Join([]string{
+			stat("Start", formatCurrency(r.StartCash)),
+			stat("Final", formatCurrency(r.FinalEquity)),
+			label.Render("Return ") + getPriceChangeStyle(r.Return).Render(fmt.Sprintf("%+.2f%%", r.Return*100)),
+			stat("Max drawdown", fmt.Sprintf("%.2f%%", r.MaxDrawdown*100)),
+		}, "   "),
+		strings.Join([]string{
+			stat("Trades", fmt.Sprintf("%d closed, %d open", r.Closed, open)),
+			stat("Wins", fmt.Sprintf("%d", r.Wins)),
+			stat("Hit rate", fmt.Sprintf("%.1f%%", r.HitRate*100)),
+		}, "   "),
+		"",
+		lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render("EQUITY"),
+	}
+
+	equity := make([]float64, len(r.Equity))
+	drawdown := make([]float64, len(r.Equity))
+	for i, p := range r.Equity {
+		equity[i] = p.Equity
+		drawdown[i] = p.Drawdown
+	}
+	chartWidth := max(20, m.width-12)
+	lines = append(lines, renderAreaChart(equity, chartWidth, backtestChartHeight)...)
+	lines = append(lines,
+		label.Render(fmt.Sprintf("%10s ", "drawdown"))+lipgloss.NewStyle().Foreground(colorNegative).Render(sparkline(invert(drawdown), chartWidth)),
+		"",
+		lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render("TRADES"),
+		m.renderTrades(),
+		HelpStyle.Render("↑/↓ j/k: scroll trades | pgup/pgdn: page | q: quit"),
+	)
+	return strings.Join(lines, "\n")
+}
+
+func invert(values []float64) []float64 {
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = 1
+		if peak > 0 {
+			out[i] = 1 - v/peak
+		}
+	}
+	return out
+}
+
+func renderAreaChart(values []float64, width, height int) []string {
+	if len(values) == 0 {
+		return []string{MutedStyle.Render("  no equity data")}
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	if hi == lo {
+		hi, lo = hi+1, lo-1
+	}
+
+	columns := make([]float64, width)
+	for i := range columns {
+		idx := 0
+		if width > 1 {
+			idx = i * (len(values) - 1) / (width - 1)
+		}
+		columns[i] = (values[idx] - lo) / (hi - lo) * float64(height)
+	}
+
+	style := lipgloss.NewStyle().Foreground(colorAccent)
+	lines := make([]string, height)
+	for row := 0; row < height; row++ {
+		level := float64(height - row - 1)
+		var b strings.Builder
+		for _, c := range columns {
+			switch {
+			case c >= level+1:
+				b.WriteRune('█')
+			case c > level:
+				b.WriteRune(sparkRunes[min(len(sparkRunes)-1, int((c-level)*float64(len(sparkRunes))))])
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		axis := ""
+		switch row {
+		case 0:
+			axis = formatCurrency(hi)
+		case height - 1:
+			axis = formatCurrency(lo)
+		}
+		lines[row] = MutedStyle.Render(fmt.Sprintf("%10s ", axis)) + style.Render(b.String())
+	}
+	return lines
+}
+
+func (m BacktestModel) renderTrades() string {
+	if len(m.report.Trades) == 0 {
+		return MutedStyle.Render("No trades: the entry rule never matched a market with a recorded quote")
+	}
+
+	widths := make([]int, len(backtestColumns))
+	headers := make([]string, len(backtestColumns))
+	used := 0
+	for i, col := range backtestColumns {
+		widths[i] = col.width
+		headers[i] = col.title
+		used += col.width + 3
+	}
+	widths[0] = max(20, m.width-used-3)
+
+	rows := []string{renderHeaderRow(headers, widths)}
+	end := min(len(m.report.Trades), m.scroll+m.tradeRows())
+	for i := m.scroll; i < end; i++ {
+		t := &m.report.Trades[i]
+		name := t.Question
+		if name == "" {
+			name = "Market " + t.MarketID
+		}
+		reason := t.Reason
+		if t.Open {
+			reason = "open (marked)"
+		}
+		cells := []string{
+			truncate(name, widths[0]),
+			strings.ToUpper(string(t.Side)),
+			t.EntryAt.Local().Format("Jan 02 15:04"),
+			fmt.Sprintf("%.3f", t.EntryPrice),
+			t.ExitAt.Local().Format("Jan 02 15:04"),
+			fmt.Sprintf("%.3f", t.ExitPrice),
+			fmt.Sprintf("%+.2f", t.PnL),
+			truncate(reason, widths[7]),
+		}
+
+		style := TableCellStyle
+		if i%2 == 0 {
+			style = StripedRowStyle
+		}
+		formatted := make([]string, len(cells))
+		for j, cell := range cells {
+			cellStyle := style
+			if j == 6 {
+				cellStyle = style.Inherit(getPriceChangeStyle(t.PnL))
+			}
+			formatted[j] = cellStyle.Render(fmt.Sprintf("%-*s", widths[j], cell))
+		}
+		rows = append(rows, joinCells(formatted))
+	}
+	if len(m.report.Trades) > m.tradeRows() {
+		rows = append(rows, MutedStyle.Render(fmt.Sprintf("Showing %d-%d of %d trades", m.scroll+1, end, len(m.report.Trades))))
+	}
+	return strings.Join(rows, "\n")
+}
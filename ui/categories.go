@@ -0,0 +1,398 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"polyterm/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type categorySort int
+
+const (
+	categoryByVolume24h categorySort = iota
+	categoryByVolume
+	categoryByLiquidity
+	categoryByOpenInterest
+	categoryByMarkets
+	categoryBySpread
+	categoryByMove
+)
+
+var categorySortNames = []string{"24h Volume", "Total Volume", "Liquidity", "Open Interest", "Markets", "Median Spread", "Avg 24h Move"}
+
+var categoryColumns = []tableColumn{
+	{title: "Category", width: 22},
+	{title: "Mkts", width: 5},
+	{title: "24h Vol", width: 9},
+	{title: "Total Vol", width: 9},
+	{title: "Liquidity", width: 9},
+	{title: "Open Int", width: 9},
+	{title: "Med Sprd", width: 8},
+	{title: "Avg |Δ|", width: 7},
+}
+
+var categorySortColumn = map[categorySort]int{
+	categoryByMarkets:      1,
+	categoryByVolume24h:    2,
+	categoryByVolume:       3,
+	categoryByLiquidity:    4,
+	categoryByOpenInterest: 5,
+	categoryBySpread:       6,
+	categoryByMove:         7,
+}
+
+const (
+	categoryBarMinWidth = 10
+	treemapMinBlock     = 4
+)
+
+func categoryValue(c *types.CategoryStats, by categorySort) float64 {
+	switch by {
+	case categoryByVolume:
+		return c.TotalVolume
+	case categoryByLiquidity:
+		return c.Liquidity
+	case categoryByOpenInterest:
+		return c.OpenInterest
+	case categoryByMarkets:
+		return float64(c.Markets)
+	case categoryBySpread:
+		return c.MedianSpread
+	case categoryByMove:
+		return c.AvgAbsChange24h
+	}
+	return c.Volume24h
+}
+
+func formatCategoryValue(c *types.CategoryStats, by categorySort) string {
+	v := categoryValue(c, by)
+	switch by {
+	case categoryByMarkets:
+		return fmt.Sprintf("%d", c.Markets)
+	case categoryBySpread:
+		return fmt.Sprintf("%.4f", v)
+	case categoryByMove:
+		return fmt.Sprintf("%.2f%%", v*100)
+	}
+	return formatCurrency(v)
+}
+
+func sortCategories(stats []types.CategoryStats, by categorySort) []types.CategoryStats {
+	sorted := make([]types.CategoryStats, len(stats))
+	copy(sorted, stats)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := categoryValue(&sorted[i], by), categoryValue(&sorted[j], by)
+		if by == categoryBySpread {
+			if a == 0 || b == 0 {
+				return b == 0 && a != 0
+			}
+			return a < b
+		}
+		return a > b
+	})
+	return sorted
+}
+
+func (m Model) categories() []types.CategoryStats {
+	return sortCategories(m.stats.Categories, m.categorySort)
+}
+
+func (m Model) categoriesHeight() int {
+	chrome := 11
+	if m.status != "" {
+		chrome++
+	}
+	return max(treemapMinBlock*2, m.height-chrome)
+}
+
+func (m *Model) moveCategoryCursor(delta int) {
+	n := len(m.stats.Categories)
+	if n == 0 {
+		return
+	}
+	m.categoryCursor = max(0, min(n-1, m.categoryCursor+delta))
+
+	rows := m.categoriesHeight() - 2
+	if m.categoryCursor < m.categoryScroll {
+		m.categoryScroll = m.categoryCursor
+	}
+	if m.categoryCursor >= m.categoryScroll+rows {
+		m.categoryScroll = m.categoryCursor - rows + 1
+	}
+}
+
+func (m *Model) openCategory() {
+	cats := m.categories()
+	if m.categoryCursor >= len(cats) {
+		return
+	}
+	m.categoryFilter = cats[m.categoryCursor].Category
+	m.currentPage = pageMarkets
+	m.resetListPosition()
+}
+
+func (m Model) renderCategories() string {
+	cats := m.categories()
+	if len(cats) == 0 {
+		return MutedStyle.Render("No data available")
+	}
+
+	view := "Table"
+	if m.categoryTreemap {
+		view = "Treemap"
+	}
+	bar := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		MutedStyle.Render("Sort: "), lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render(categorySortNames[m.categorySort]),
+		MutedStyle.Render("  View: "), lipgloss.NewStyle().Foreground(colorPrimary).Bold(true).Render(view),
+		MutedStyle.Render(fmt.Sprintf("  Categories: %d", len(cats))),
+	)
+
+	var body string
+	if m.categoryTreemap {
+		body = m.renderTreemap(cats)
+	} else {
+		body = m.renderCategoryTable(cats)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, bar, "", body)
+}
+
+func (m Model) renderCategoryTable(cats []types.CategoryStats) string {
+	widths := make([]int, len(categoryColumns))
+	headers := make([]string, len(categoryColumns))
+	used := 0
+	for i, col := range categoryColumns {
+		widths[i] = col.width
+		headers[i] = col.title
+		if categorySortColumn[m.categorySort] == i {
+			headers[i] += " ▼"
+			widths[i] = max(widths[i], lipgloss.Width(headers[i]))
+		}
+		used += widths[i] + 3
+	}
+	barWidth := max(categoryBarMinWidth, m.width-used-3)
+	widths = append(widths, barWidth)
+	headers = append(headers, categorySortNames[m.categorySort])
+
+	maxValue := 0.0
+	for i := range cats {
+		maxValue = max(maxValue, categoryValue(&cats[i], m.categorySort))
+	}
+
+	rows := []string{renderHeaderRow(headers, widths)}
+	visible := m.categoriesHeight() - 2
+	end := min(len(cats), m.categoryScroll+visible)
+	for i := m.categoryScroll; i < end; i++ {
+		c := &cats[i]
+		cells := []string{
+			truncate(c.Category, widths[0]),
+			fmt.Sprintf("%d", c.Markets),
+			formatCurrency(c.Volume24h),
+			formatCurrency(c.TotalVolume),
+			formatCurrency(c.Liquidity),
+			formatCurrency(c.OpenInterest),
+			fmt.Sprintf("%.4f", c.MedianSpread),
+			fmt.Sprintf("%.2f%%", c.AvgAbsChange24h*100),
+		}
+
+		style := TableCellStyle
+		if i == m.categoryCursor {
+			style = SelectedRowStyle
+		} else if i%2 == 0 {
+			style = StripedRowStyle
+		}
+
+		formatted := make([]string, 0, len(cells)+1)
+		for j, cell := range cells {
+			formatted = append(formatted, style.Render(fmt.Sprintf("%-*s", widths[j], cell)))
+		}
+
+		value := categoryValue(c, m.categorySort)
+		label := formatCategoryValue(c, m.categorySort)
+		formatted = append(formatted, renderBar(value, maxValue, barWidth, label, style))
+		rows = append(rows, joinCells(formatted))
+	}
+
+	if len(cats) > visible {
+		rows = append(rows, MutedStyle.Render(fmt.Sprintf("Showing %d-%d of %d categories", m.categoryScroll+1, end, len(cats))))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func renderBar(value, maxValue float64, width int, label string, style lipgloss.Style) string {
+	barWidth := width - lipgloss.Width(label) - 1
+	filled := 0
+	if maxValue > 0 && barWidth > 0 {
+		filled = int(value / maxValue * float64(barWidth))
+		if value > 0 && filled == 0 {
+			filled = 1
+		}
+	}
+
+	base := style.UnsetPadding()
+	barStyle := base.Foreground(colorAccent)
+	return base.Render(" ") +
+		barStyle.Render(strings.Repeat("█", filled)) +
+		base.Render(strings.Repeat(" ", max(0, barWidth-filled))+" "+label+" ")
+}
+
+type rect struct {
+	x, y, w, h int
+}
+
+func treemapLayout(weights []float64, r rect) []rect {
+	rects := make([]rect, len(weights))
+	var split func(lo, hi int, r rect)
+	split = func(lo, hi int, r rect) {
+		if hi-lo == 1 {
+			rects[lo] = r
+			return
+		}
+
+		total := 0.0
+		for _, w := range weights[lo:hi] {
+			total += w
+		}
+		mid, acc := lo+1, weights[lo]
+		for mid < hi-1 && acc+weights[mid] <= total/2 {
+			acc += weights[mid]
+			mid++
+		}
+		share := 0.5
+		if total > 0 {
+			share = acc / total
+		}
+
+		if r.w >= r.h*2 {
+			w := max(1, min(r.w-1, int(float64(r.w)*share+0.5)))
+			split(lo, mid, rect{r.x, r.y, w, r.h})
+			split(mid, hi, rect{r.x + w, r.y, r.w - w, r.h})
+		} else {
+			h := max(1, min(r.h-1, int(float64(r.h)*share+0.5)))
+			split(lo, mid, rect{r.x, r.y, r.w, h})
+			split(mid, hi, rect{r.x, r.y + h, r.w, r.h - h})
+		}
+	}
+	if len(weights) > 0 {
+		split(0, len(weights), r)
+	}
+	return rects
+}
+
+func (m Model) renderTreemap(cats []types.CategoryStats) string {
+	selected := ""
+	if m.categoryCursor < len(cats) {
+		selected = cats[m.categoryCursor].Category
+	}
+
+	by := m.categorySort
+	if by == categoryBySpread || by == categoryByMove {
+		by = categoryByVolume24h
+		cats = sortCategories(cats, by)
+	}
+
+	width := max(treemapMinBlock*4, m.width-1)
+	height := m.categoriesHeight()
+	maxBlocks := (width / (treemapMinBlock * 3)) * (height / treemapMinBlock)
+
+	var weights []float64
+	var total float64
+	for i := range cats {
+		total += categoryValue(&cats[i], by)
+	}
+	shown := 0
+	for i := range cats {
+		v := categoryValue(&cats[i], by)
+		if v <= 0 || shown >= maxBlocks {
+			break
+		}
+		weights = append(weights, v)
+		shown++
+	}
+	if len(weights) == 0 {
+		return MutedStyle.Render("Nothing to chart for " + categorySortNames[by])
+	}
+
+	rects := treemapLayout(weights, rect{0, 0, width, height})
+	owner := make([][]int, height)
+	for y := range owner {
+		owner[y] = make([]int, width)
+		for x := range owner[y] {
+			owner[y][x] = -1
+		}
+	}
+	for i, r := range rects {
+		for y := r.y; y < r.y+r.h; y++ {
+			for x := r.x; x < r.x+r.w; x++ {
+				owner[y][x] = i
+			}
+		}
+	}
+
+	labels := make([][]string, len(rects))
+	for i, r := range rects {
+		c := &cats[i]
+		lines := []string{
+			c.Category,
+			fmt.Sprintf("%s  %.1f%%", formatCategoryValue(c, by), weights[i]/total*100),
+			fmt.Sprintf("%d markets", c.Markets),
+		}
+		for j := range lines {
+			if j >= r.h {
+				lines = lines[:j]
+				break
+			}
+			lines[j] = truncate(lines[j], max(0, r.w-2))
+		}
+		labels[i] = lines
+	}
+
+	fills := []lipgloss.TerminalColor{colorPrimary, colorSecondary, colorAccent, colorPositive, colorWarning, colorNegative, colorTabActive, colorDim}
+	glyphs := []rune{' ', '░', '▒', '▓', '·', ':'}
+
+	var lines []string
+	for y := 0; y < height; y++ {
+		var b strings.Builder
+		for x := 0; x < width; {
+			i := owner[y][x]
+			end := x
+			for end < width && owner[y][end] == i {
+				end++
+			}
+
+			r := rects[i]
+			runes := make([]rune, end-x)
+			for k := range runes {
+				runes[k] = ' '
+				if colorless() {
+					runes[k] = glyphs[i%len(glyphs)]
+				}
+			}
+			if row := y - r.y; row < len(labels[i]) {
+				for k, ch := range []rune(labels[i][row]) {
+					if pos := r.x + 1 + k - x; pos >= 0 && pos < len(runes) {
+						runes[pos] = ch
+					}
+				}
+			}
+
+			style := lipgloss.NewStyle().Background(fills[i%len(fills)]).Foreground(colorTabText)
+			if cats[i].Category == selected {
+				style = style.Bold(true).Underline(true).Reverse(true)
+			}
+			b.WriteString(style.Render(string(runes)))
+			x = end
+		}
+		lines = append(lines, b.String())
+	}
+
+	if shown < len(cats) {
+		lines = append(lines, MutedStyle.Render(fmt.Sprintf("Showing top %d of %d categories by %s", shown, len(cats), categorySortNames[by])))
+	}
+	return strings.Join(lines, "\n")
+}
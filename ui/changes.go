@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"polyterm/diff"
+	"polyterm/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const flashDuration = 3 * time.Second
+
+var flashColumns = map[int]string{
+	2: "yes",
+	3: "no",
+	4: "volume",
+	5: "volume24hr",
+}
+
+type flashExpiredMsg time.Time
+
+type changeState struct {
+	at      time.Time
+	flashes map[string]map[string]diff.Delta
+	added   int
+	removed int
+	listed  map[string]time.Time
+}
+
+func (m *Model) trackChanges(prev, next []types.Market) tea.Cmd {
+	if len(prev) == 0 {
+		return nil
+	}
+	r := diff.Compare(prev, next)
+	now := time.Now()
+
+	c := &m.changes
+	c.at, c.added, c.removed = now, len(r.Added), len(r.Removed)
+	c.flashes = make(map[string]map[string]diff.Delta, len(r.Changed))
+	for _, change := range r.Changed {
+		c.flashes[change.ID] = change.Fields
+	}
+	if c.listed == nil {
+		c.listed = make(map[string]time.Time)
+	}
+	for i := range r.Added {
+		c.listed[r.Added[i].ID] = now
+	}
+	for i := range r.Removed {
+		delete(c.listed, r.Removed[i].ID)
+	}
+
+	if len(c.flashes) == 0 {
+		return nil
+	}
+	return tea.Tick(flashDuration, func(time.Time) tea.Msg {
+		return flashExpiredMsg(now)
+	})
+}
+
+func (m *Model) expireFlashes(at time.Time) {
+	if m.changes.at.Equal(at) {
+		m.changes.flashes = nil
+	}
+}
+
+func (m Model) flashStyles(id string) map[int]lipgloss.Style {
+	fields := m.changes.flashes[id]
+	if fields == nil {
+		return nil
+	}
+	styles := make(map[int]lipgloss.Style)
+	for col, field := range flashColumns {
+		if d, ok := fields[field]; ok {
+			styles[col] = FlashUpStyle
+			if d.Change() < 0 {
+				styles[col] = FlashDownStyle
+			}
+		}
+	}
+	return styles
+}
+
+func (m Model) renderChangeCounts() string {
+	c := m.changes
+	if c.added == 0 && c.removed == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(colorPositive).Render(fmt.Sprintf("+%d new", c.added)) +
+		MutedStyle.Render(" / ") +
+		lipgloss.NewStyle().Foreground(colorNegative).Render(fmt.Sprintf("-%d gone", c.removed))
+}
+
+func getJustListed(markets []types.Market, listed map[string]time.Time, limit int) []types.Market {
+	var filtered []types.Market
+	for _, m := range markets {
+		if _, ok := listed[m.ID]; ok {
+			filtered = append(filtered, m)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return listed[filtered[i].ID].After(listed[filtered[j].ID])
+	})
+
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered
+}
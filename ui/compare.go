@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"polyterm/api"
+	"polyterm/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	minCompare         = 2
+	maxCompare         = 4
+	compareLabelWidth  = 14
+	compareChartHeight = 12
+)
+
+var compareGlyphs = []rune{'●', '◆', '▲', '■'}
+
+type pricePoint struct {
+	at  time.Time
+	yes float64
+}
+
+func compareColors() []lipgloss.TerminalColor {
+	return []lipgloss.TerminalColor{colorPrimary, colorAccent, colorWarning, colorSecondary}
+}
+
+func actionCompare(m *Model, _ string) tea.Cmd {
+	targets := m.markedMarkets()
+	if len(targets) < minCompare || len(targets) > maxCompare {
+		m.setError(fmt.Errorf("select %d to %d markets to compare (%d selected)", minCompare, maxCompare, len(targets)))
+		return nil
+	}
+	m.compare = targets
+	m.currentView = viewCompare
+	m.clearSelection()
+	return nil
+}
+
+func (m Model) comparedMarkets() []types.Market {
+	out := make([]types.Market, len(m.compare))
+	for i := range m.compare {
+		out[i] = m.compare[i]
+		if j := marketIndex(m.markets, m.compare[i].ID); j >= 0 {
+			out[i] = m.markets[j]
+		}
+	}
+	return out
+}
+
+func (m Model) priceHistory(market *types.Market) ([]pricePoint, bool) {
+	if points, ok := m.storedHistory(market); ok {
+		return points, true
+	}
+	return m.reconstructedHistory(market), false
+}
+
+func (m Model) storedHistory(market *types.Market) ([]pricePoint, bool) {
+	if m.store == nil {
+		return nil, false
+	}
+	history := m.store.History(market.ID)
+	if len(history) < 2 {
+		return nil, false
+	}
+	points := make([]pricePoint, len(history))
+	for i, p := range history {
+		points[i] = pricePoint{p.At, p.Yes}
+	}
+	return points, true
+}
+
+func (m Model) reconstructedHistory(market *types.Market) []pricePoint {
+	now := m.lastUpdate
+	if now.IsZero() {
+		now = time.Now()
+	}
+	yes, _ := api.ParseOdds(market)
+	yes /= 100
+	steps := []struct {
+		ago    time.Duration
+		change float64
+	}{
+		{30 * 24 * time.Hour, market.OneMonthPriceChange},
+		{7 * 24 * time.Hour, market.OneWeekPriceChange},
+		{24 * time.Hour, market.OneDayPriceChange},
+		{time.Hour, market.OneHourPriceChange},
+	}
+	var points []pricePoint
+	for _, s := range steps {
+		points = append(points, pricePoint{now.Add(-s.ago), max(0, min(1, yes-s.change))})
+	}
+	return append(points, pricePoint{now, yes})
+}
+
+func (m Model) renderCompare() string {
+	markets := m.comparedMarkets()
+	colors := compareColors()
+	colWidth := max(16, min(32, (m.width-compareLabelWidth-4)/max(1, len(markets))))
+
+	label := lipgloss.NewStyle().Foreground(colorMuted).Width(compareLabelWidth)
+	cell := lipgloss.NewStyle().Width(colWidth).PaddingRight(2)
+	row := func(name string, values func(market *types.Market) (string, lipgloss.Style)) string {
+		cells := []string{label.Render(name)}
+		for i := range markets {
+			v, style := values(&markets[i])
+			cells = append(cells, cell.Inherit(style).Render(truncate(v, colWidth-2)))
+		}
+		return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+	}
+	plain := lipgloss.NewStyle().Foreground(colorText)
+	change := func(get func(market *types.Market) float64) func(market *types.Market) (string, lipgloss.Style) {
+		return func(market *types.Market) (string, lipgloss.Style) {
+			c := get(market)
+			return fmt.Sprintf("%+.2f%%", c*100), getPriceChangeStyle(c)
+		}
+	}
+
+	titles := []string{label.Render("")}
+	questions := []string{label.Render("Market")}
+	for i := range markets {
+		style := lipgloss.NewStyle().Foreground(colors[i%len(colors)]).Bold(true)
+		titles = append(titles, cell.Inherit(style).Render(fmt.Sprintf("%c %d", compareGlyphs[i%len(compareGlyphs)], i+1)))
+		q := markets[i].Question
+		if marketIndex(m.markets, markets[i].ID) < 0 {
+			q += " (gone)"
+		}
+		questions = append(questions, cell.Inherit(plain).Render(wrapLines(q, colWidth-2, 3)))
+	}
+
+	lines := []string{
+		"",
+		BrandStyle.Render("POLYTERM") + " " + HeaderStyle.Render(fmt.Sprintf("Compare %d markets", len(markets))),
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Top, titles...),
+		lipgloss.JoinHorizontal(lipgloss.Top, questions...),
+		"",
+		row("Yes", func(market *types.Market) (string, lipgloss.Style) {
+			yes, _ := api.ParseOdds(market)
+			return fmt.Sprintf("%.1f%%", yes), YesOddsStyle.UnsetPadding()
+		}),
+		row("No", func(market *types.Market) (string, lipgloss.Style) {
+			_, no := api.ParseOdds(market)
+			return fmt.Sprintf("%.1f%%", no), NoOddsStyle.UnsetPadding()
+		}),
+		row("Spread", func(market *types.Market) (string, lipgloss.Style) {
+			return fmt.Sprintf("%.1f¢", market.GetSpread()*100), plain
+		}),
+		row("24h Volume", func(market *types.Market) (string, lipgloss.Style) {
+			return formatCurrency(market.Volume24hr), VolumeStyle.UnsetPadding()
+		}),
+		row("Total Volume", func(market *types.Market) (string, lipgloss.Style) {
+			return formatCurrency(market.GetVolume()), plain
+		}),
+		row("Liquidity", func(market *types.Market) (string, lipgloss.Style) {
+			return formatCurrency(market.GetLiquidity()), plain
+		}),
+		row("1h Change", change(func(market *types.Market) float64 { return market.OneHourPriceChange })),
+		row("1d Change", change(func(market *types.Market) float64 { return market.OneDayPriceChange })),
+		row("1w Change", change(func(market *types.Market) float64 { return market.OneWeekPriceChange })),
+		row("1mo Change", change(func(market *types.Market) float64 { return market.OneMonthPriceChange })),
+		row("Ends", func(market *types.Market) (string, lipgloss.Style) {
+			end, err := time.Parse(time.RFC3339, market.EndDate)
+			if err != nil {
+				return formatEndDate(market.EndDate), plain
+			}
+			return fmt.Sprintf("%s (%s)", end.Format("Jan 02, 2006"), formatRemaining(time.Until(end))), plain
+		}),
+		"",
+	}
+
+	series := make([][]pricePoint, len(markets))
+	stored := true
+	for i := range markets {
+		var ok bool
+		series[i], ok = m.storedHistory(&markets[i])
+		stored = stored && ok
+	}
+	source := "stored snapshots"
+	if !stored {
+		source = "reconstructed from 1mo/1w/1d/1h changes (not every market has stored snapshots)"
+		for i := range markets {
+			series[i] = m.reconstructedHistory(&markets[i])
+		}
+	}
+	lines = append(lines, lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render("YES PRICE HISTORY")+"  "+MutedStyle.Render(source))
+	height := max(4, min(compareChartHeight, m.height-len(lines)-10))
+	lines = append(lines, renderCompareChart(series, max(20, m.width-12), height)...)
+	lines = append(lines, "", m.renderHelp())
+	return strings.Join(lines, "\n")
+}
+
+func renderCompareChart(series [][]pricePoint, width, height int) []string {
+	var from, to time.Time
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, points := range series {
+		for _, p := range points {
+			if from.IsZero() || p.at.Before(from) {
+				from = p.at
+			}
+			if p.at.After(to) {
+				to = p.at
+			}
+			lo, hi = min(lo, p.yes), max(hi, p.yes)
+		}
+	}
+	if from.IsZero() || !to.After(from) {
+		return []string{MutedStyle.Render("  not enough price history yet")}
+	}
+	pad := max(0.01, (hi-lo)*0.05)
+	lo, hi = max(0, lo-pad), min(1, hi+pad)
+
+	grid := make([][]int, height)
+	for row := range grid {
+		grid[row] = make([]int, width)
+		for col := range grid[row] {
+			grid[row][col] = -1
+		}
+	}
+	span := to.Sub(from)
+	for s, points := range series {
+		for col := 0; col < width; col++ {
+			t := from.Add(time.Duration(float64(span) * float64(col) / float64(max(1, width-1))))
+			i := sort.Search(len(points), func(i int) bool { return points[i].at.After(t) }) - 1
+			if i < 0 {
+				continue
+			}
+			row := height - 1 - int(math.Round((points[i].yes-lo)/(hi-lo)*float64(height-1)))
+			grid[max(0, min(height-1, row))][col] = s
+		}
+	}
+
+	colors := compareColors()
+	lines := make([]string, 0, height+1)
+	for row := range grid {
+		axis := ""
+		switch row {
+		case 0:
+			axis = fmt.Sprintf("%.1f%%", hi*100)
+		case height / 2:
+			axis = fmt.Sprintf("%.1f%%", (hi+lo)/2*100)
+		case height - 1:
+			axis = fmt.Sprintf("%.1f%%", lo*100)
+		}
+		var b strings.Builder
+		b.WriteString(MutedStyle.Render(fmt.Sprintf("%9s ", axis)))
+		for _, s := range grid[row] {
+			if s < 0 {
+				b.WriteByte(' ')
+				continue
+			}
+			b.WriteString(lipgloss.NewStyle().Foreground(colors[s%len(colors)]).Render(string(compareGlyphs[s%len(compareGlyphs)])))
+		}
+		lines = append(lines, b.String())
+	}
+
+	start, end := formatTime(from), formatTime(to)
+	gap := max(1, width-len(start)-len(end))
+	lines = append(lines, MutedStyle.Render(strings.Repeat(" ", 10)+start+strings.Repeat(" ", gap)+end))
+	return lines
+}
+
+func wrapLines(s string, width, maxLines int) string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = truncate(lines[maxLines-1]+"...", width)
+	}
+	for i := range lines {
+		lines[i] = truncate(lines[i], width)
+	}
+	return strings.Join(lines, "\n")
+}
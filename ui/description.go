@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const minDescriptionHeight = 5
+
+type descState struct {
+	view    viewport.Model
+	id      string
+	text    string
+	width   int
+	query   string
+	matches []int
+	match   int
+}
+
+func (m Model) descriptionWidth() int {
+	return max(20, m.width-12)
+}
+
+func (m Model) descriptionHeight() int {
+	top, bottom := m.detailSections()
+	chrome := lipgloss.Height(lipgloss.JoinVertical(lipgloss.Left, top...)) + lipgloss.Height(lipgloss.JoinVertical(lipgloss.Left, bottom...))
+	chrome += 4
+	if resolutionSource(m.selected.ResolutionSource, m.selected.Description) != "" {
+		chrome++
+	}
+	return max(minDescriptionHeight, m.height-chrome)
+}
+
+func (m *Model) syncDescription() {
+	if m.currentView != viewDetail || m.selectedID == "" {
+		return
+	}
+	d := &m.desc
+	d.view.Width = m.descriptionWidth()
+	d.view.Height = m.descriptionHeight()
+
+	market := m.selected
+	if d.id == market.ID && d.text == market.Description && d.width == d.view.Width && d.query == m.detailQuery {
+		return
+	}
+	if d.id != market.ID {
+		d.view.GotoTop()
+	}
+	d.id, d.text, d.width, d.query = market.ID, market.Description, d.view.Width, m.detailQuery
+
+	lines := renderMarkdown(market.Description, d.view.Width, d.query)
+	d.view.SetContent(strings.Join(lines, "\n"))
+
+	d.matches, d.match = nil, 0
+	if q := strings.ToLower(strings.TrimSpace(d.query)); q != "" {
+		for i, line := range lines {
+			if strings.Contains(strings.ToLower(stripANSI(line)), q) {
+				d.matches = append(d.matches, i)
+			}
+		}
+	}
+}
+
+func (m *Model) showMatch(i int) {
+	d := &m.desc
+	if len(d.matches) == 0 {
+		return
+	}
+	d.match = (i%len(d.matches) + len(d.matches)) % len(d.matches)
+	d.view.SetYOffset(d.matches[d.match] - d.view.Height/3)
+}
+
+func (m Model) renderDescription() string {
+	market := m.selected
+	title := lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render("MARKET DETAILS")
+
+	var info []string
+	if m.desc.view.TotalLineCount() > m.desc.view.Height {
+		info = append(info, fmt.Sprintf("%d%%", int(m.desc.view.ScrollPercent()*100)))
+	}
+	switch {
+	case m.searchMode:
+		info = append(info, "/"+m.detailQuery+"_")
+	case m.detailQuery != "" && len(m.desc.matches) == 0:
+		info = append(info, fmt.Sprintf("no matches for %q", m.detailQuery))
+	case m.detailQuery != "":
+		info = append(info, fmt.Sprintf("%q %d/%d", m.detailQuery, m.desc.match+1, len(m.desc.matches)))
+	}
+	if len(info) > 0 {
+		title += "  " + MutedStyle.Render(strings.Join(info, "  "))
+	}
+
+	lines := []string{title}
+	if source := resolutionSource(market.ResolutionSource, market.Description); source != "" {
+		link := lipgloss.NewStyle().Foreground(colorPrimary).Underline(true).Render(truncate(source, m.descriptionWidth()-19))
+		if mdURL.MatchString(source) {
+			link = hyperlink(source, link)
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(colorWarning).Bold(true).Render("Resolution source: ")+link)
+	}
+	lines = append(lines, m.desc.view.View())
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorSecondary).
+		Padding(0, 2).
+		Width(m.width - 8).
+		Render(strings.Join(lines, "\n"))
+}
+
+func actionScrollDescription(lines int) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		if lines < 0 {
+			m.desc.view.ScrollUp(-lines)
+		} else {
+			m.desc.view.ScrollDown(lines)
+		}
+		return nil
+	}
+}
+
+func actionPageDescription(dir int) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		if dir < 0 {
+			m.desc.view.PageUp()
+		} else {
+			m.desc.view.PageDown()
+		}
+		return nil
+	}
+}
+
+func actionDescriptionMatch(delta int) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		if len(m.desc.matches) == 0 {
+			m.setStatus("No search matches in the description")
+			return nil
+		}
+		m.showMatch(m.desc.match + delta)
+		return nil
+	}
+}
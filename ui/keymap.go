@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"polyterm/config"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+const defaultPreset = "default"
+
+var defaultBindings = map[string][]string{
+	"quit":            {"q", "ctrl+c"},
+	"back":            {"esc"},
+	"palette":         {":", "ctrl+p"},
+	"help":            {"?"},
+	"refresh":         {"r"},
+	"autorefresh":     {"a"},
+	"page.next":       {"tab"},
+	"page.markets":    {"1"},
+	"page.analytics":  {"2"},
+	"page.categories": {"3"},
+	"page.arbitrage":  {"4"},
+	"page.resolved":   {"5"},
+	"search.start":    {"/"},
+	"filter.cycle":    {"f"},
+	"sort.cycle":      {"s"},
+	"clear":           {"c"},
+	"detail.open":     {"enter"},
+	"market.browser":  {"o"},
+	"nav.up":          {"up", "k"},
+	"nav.down":        {"down", "j"},
+	"nav.top":         {"home", "g"},
+	"nav.bottom":      {"end", "G"},
+	"nav.pageup":      {"pageup"},
+	"nav.pagedown":    {"pagedown"},
+	"panel.next":      {"right", "l"},
+	"panel.prev":      {"left", "h"},
+	"panel.window":    {"w"},
+	"category.sort":   {"s"},
+	"category.view":   {"v"},
+	"arb.all":         {"o"},
+	"resolved.search": {"/"},
+	"resolved.range":  {"d"},
+	"select.toggle":   {" "},
+	"select.visual":   {"v"},
+	"select.up":       {"shift+up", "K"},
+	"select.down":     {"shift+down", "J"},
+	"watch.toggle":    {"w"},
+	"copy.ids":        {"y"},
+	"copy.slugs":      {"Y"},
+	"compare":         {"C"},
+	"layout.split":    {"L"},
+	"detail.up":       {"up", "k"},
+	"detail.down":     {"down", "j"},
+	"detail.pageup":   {"pageup"},
+	"detail.pagedown": {"pagedown", " "},
+	"detail.search":   {"/"},
+	"detail.next":     {"n"},
+	"detail.prev":     {"N"},
+	"replay.toggle":   {"p"},
+	"replay.faster":   {"+", "="},
+	"replay.slower":   {"-"},
+	"replay.prev":     {"["},
+	"replay.next":     {"]"},
+	"replay.back":     {"<"},
+	"replay.forward":  {">"},
+}
+
+var presets = map[string]map[string][]string{
+	defaultPreset: {},
+	"vim": {
+		"nav.pageup":   {"pageup", "ctrl+u", "ctrl+b"},
+		"nav.pagedown": {"pagedown", "ctrl+d", "ctrl+f"},
+	},
+	"emacs": {
+		"palette":      {":", "alt+x"},
+		"back":         {"esc", "ctrl+g"},
+		"search.start": {"/", "ctrl+s"},
+		"nav.up":       {"up", "ctrl+p"},
+		"nav.down":     {"down", "ctrl+n"},
+		"nav.top":      {"home", "alt+<"},
+		"nav.bottom":   {"end", "alt+>"},
+		"nav.pageup":   {"pageup", "alt+v"},
+		"nav.pagedown": {"pagedown", "ctrl+v"},
+	},
+}
+
+type keyMap struct {
+	preset   string
+	bindings map[string]key.Binding
+}
+
+func newKeyMap(cfg config.Keymap) (keyMap, error) {
+	preset := cfg.Preset
+	if preset == "" {
+		preset = defaultPreset
+	}
+	overlay, ok := presets[preset]
+	if !ok {
+		return keyMap{}, fmt.Errorf("unknown keymap preset %q (available: %s)", preset, strings.Join(presetNames(), ", "))
+	}
+
+	keys := make(map[string][]string, len(defaultBindings))
+	for id, k := range defaultBindings {
+		keys[id] = k
+	}
+	for id, k := range overlay {
+		keys[id] = k
+	}
+
+	var errs []error
+	for id, k := range cfg.Bindings {
+		if findAction(id) == nil {
+			errs = append(errs, fmt.Errorf("keymap: unknown action %q", id))
+			continue
+		}
+		keys[id] = k
+	}
+
+	km := keyMap{preset: preset, bindings: make(map[string]key.Binding, len(keys))}
+	for id, k := range keys {
+		if len(k) == 0 {
+			continue
+		}
+		km.bindings[id] = key.NewBinding(
+			key.WithKeys(k...),
+			key.WithHelp(keyLabel(k), findAction(id).title),
+		)
+	}
+
+	errs = append(errs, km.conflicts()...)
+	return km, errors.Join(errs...)
+}
+
+func (k keyMap) binding(id string) key.Binding {
+	return k.bindings[id]
+}
+
+func (k keyMap) conflicts() []error {
+	var errs []error
+	for i := range actions {
+		for j := i + 1; j < len(actions); j++ {
+			a, b := &actions[i], &actions[j]
+			if !scopesOverlap(a.scope, b.scope) {
+				continue
+			}
+			for _, ka := range k.binding(a.id).Keys() {
+				for _, kb := range k.binding(b.id).Keys() {
+					if ka == kb {
+						errs = append(errs, fmt.Errorf("keymap: %q is bound to both %q and %q", ka, a.id, b.id))
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func scopesOverlap(a, b actionScope) bool {
+	if a == scopeGlobal || b == scopeGlobal || a == scopeReplay || b == scopeReplay || a == b {
+		return true
+	}
+	if a == scopeMarket || b == scopeMarket {
+		other := a
+		if other == scopeMarket {
+			other = b
+		}
+		return other == scopeList || other == scopeMarkets || other == scopeDetail
+	}
+	if a == scopeList || b == scopeList {
+		return a != scopeDetail && b != scopeDetail
+	}
+	return false
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func keyLabel(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			k = "↑"
+		case "down":
+			k = "↓"
+		case "pageup":
+			k = "PgUp"
+		case "pagedown":
+			k = "PgDn"
+		case " ":
+			k = "space"
+		}
+		labels[i] = k
+	}
+	return strings.Join(labels, "/")
+}
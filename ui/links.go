@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"polyterm/api"
+	"polyterm/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type marketRefResult struct {
+	ref     api.MarketRef
+	markets []types.Market
+	err     error
+}
+
+type browserResult struct {
+	url string
+	err error
+}
+
+func (m Model) WithOpen(ref api.MarketRef) Model {
+	m.pendingOpen = &ref
+	return m
+}
+
+func hyperlinksEnabled() bool {
+	if os.Getenv("POLYTERM_NO_HYPERLINKS") != "" {
+		return false
+	}
+	switch os.Getenv("TERM") {
+	case "", "dumb", "linux":
+		return false
+	}
+	return true
+}
+
+func hyperlink(url, text string) string {
+	if url == "" || !hyperlinksEnabled() {
+		return text
+	}
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+func openURLCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+		if err := cmd.Start(); err != nil {
+			return browserResult{url, err}
+		}
+		go cmd.Wait()
+		return browserResult{url, nil}
+	}
+}
+
+func actionOpenBrowser(m *Model, _ string) tea.Cmd {
+	market := m.currentMarket()
+	if market == nil {
+		m.setError(fmt.Errorf("no market selected"))
+		return nil
+	}
+	return openURLCmd(api.MarketURL(market))
+}
+
+func (m *Model) applyBrowserResult(msg browserResult) {
+	if msg.err != nil {
+		m.setError(fmt.Errorf("opening %s: %w", msg.url, msg.err))
+		return
+	}
+	m.setStatus("Opened %s", msg.url)
+}
+
+func fetchMarketRefCmd(ref api.MarketRef) tea.Cmd {
+	return func() tea.Msg {
+		markets, err := api.FetchMarketRef(context.Background(), ref)
+		return marketRefResult{ref, markets, err}
+	}
+}
+
+func (m *Model) openPending() tea.Cmd {
+	ref := m.pendingOpen
+	if ref == nil || len(m.markets) == 0 {
+		return nil
+	}
+	if i := api.FindMarket(m.markets, *ref); i >= 0 {
+		m.pendingOpen = nil
+		if !m.openMarket(m.markets[i].ID) {
+			m.selectMarket(&m.markets[i])
+		}
+		return nil
+	}
+	if m.replaying() {
+		m.pendingOpen = nil
+		m.setError(fmt.Errorf("market %s is not in the recording", ref))
+		return nil
+	}
+	return fetchMarketRefCmd(*ref)
+}
+
+func (m *Model) applyMarketRefResult(msg marketRefResult) {
+	m.pendingOpen = nil
+	if msg.err != nil {
+		m.setError(msg.err)
+		return
+	}
+	i := api.FindMarket(msg.markets, msg.ref)
+	if i < 0 && len(msg.markets) > 0 {
+		i = 0
+	}
+	if i < 0 {
+		m.setError(fmt.Errorf("market %s not found", msg.ref))
+		return
+	}
+	m.selectMarket(&msg.markets[i])
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	mdInline  = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)|\*\*([^*]+)\*\*|__([^_]+)__|(https?://[^\s<>()\[\]"']*[^\s<>()\[\]"'.,;:!?])`)
+	mdHeading = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	mdBullet  = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.*)$`)
+	mdURL     = regexp.MustCompile(`https?://[^\s<>()\[\]"']*[^\s<>()\[\]"'.,;:!?]`)
+	mdSource  = regexp.MustCompile(`(?i)resolution source|resolve[sd]? (?:according to|based on)|source:`)
+	ansiCodes = regexp.MustCompile("\x1b\\[[0-9;:]*[A-Za-z]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)")
+)
+
+type mdStyles struct {
+	text, bold, heading, link, url, match lipgloss.Style
+}
+
+func newMDStyles() mdStyles {
+	text := lipgloss.NewStyle().Foreground(colorSubtle)
+	st := mdStyles{
+		text:    text,
+		bold:    text.Foreground(colorBright).Bold(true),
+		heading: lipgloss.NewStyle().Foreground(colorSecondary).Bold(true),
+		link:    lipgloss.NewStyle().Foreground(colorPrimary).Underline(true),
+		url:     MutedStyle,
+		match:   lipgloss.NewStyle().Foreground(colorSurface).Background(colorWarning).Bold(true),
+	}
+	if colorless() {
+		st.match = st.match.Reverse(true)
+	}
+	return st
+}
+
+func renderMarkdown(text string, width int, query string) []string {
+	st := newMDStyles()
+	var find *regexp.Regexp
+	if query = strings.TrimSpace(query); query != "" {
+		find = regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	}
+
+	var out []string
+	blank := true
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+			if !blank {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		case mdHeading.MatchString(line):
+			if !blank {
+				out = append(out, "")
+			}
+			body := renderInline(mdHeading.FindStringSubmatch(line)[1], st.heading, st, find)
+			out = append(out, wrapStyled(body, width)...)
+		case mdBullet.MatchString(line):
+			body := renderInline(mdBullet.FindStringSubmatch(line)[1], st.text, st, find)
+			for i, l := range wrapStyled(body, width-2) {
+				prefix := "  "
+				if i == 0 {
+					prefix = st.heading.Render("•") + " "
+				}
+				out = append(out, prefix+l)
+			}
+		default:
+			out = append(out, wrapStyled(renderInline(line, st.text, st, find), width)...)
+		}
+		blank = false
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+func renderInline(s string, base lipgloss.Style, st mdStyles, find *regexp.Regexp) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range mdInline.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(highlight(s[last:loc[0]], base, st.match, find))
+		switch {
+		case loc[2] >= 0:
+			url := s[loc[4]:loc[5]]
+			b.WriteString(hyperlink(url, highlight(s[loc[2]:loc[3]], st.link, st.match, find)))
+			b.WriteString(highlight(" ("+url+")", st.url, st.match, find))
+		case loc[6] >= 0:
+			b.WriteString(highlight(s[loc[6]:loc[7]], st.bold, st.match, find))
+		case loc[8] >= 0:
+			b.WriteString(highlight(s[loc[8]:loc[9]], st.bold, st.match, find))
+		default:
+			url := s[loc[10]:loc[11]]
+			b.WriteString(hyperlink(url, highlight(url, st.link, st.match, find)))
+		}
+		last = loc[1]
+	}
+	b.WriteString(highlight(s[last:], base, st.match, find))
+	return b.String()
+}
+
+func highlight(s string, base, match lipgloss.Style, find *regexp.Regexp) string {
+	if s == "" {
+		return ""
+	}
+	if find == nil {
+		return base.Render(s)
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range find.FindAllStringIndex(s, -1) {
+		if loc[0] > last {
+			b.WriteString(base.Render(s[last:loc[0]]))
+		}
+		b.WriteString(match.Render(s[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	if last < len(s) {
+		b.WriteString(base.Render(s[last:]))
+	}
+	return b.String()
+}
+
+func wrapStyled(s string, width int) []string {
+	return strings.Split(lipgloss.NewStyle().Width(max(1, width)).Render(s), "\n")
+}
+
+func stripANSI(s string) string {
+	return ansiCodes.ReplaceAllString(s, "")
+}
+
+func resolutionSource(source, description string) string {
+	if source = strings.TrimSpace(source); source != "" {
+		if url := mdURL.FindString(source); url != "" {
+			return url
+		}
+		return source
+	}
+	if loc := mdSource.FindStringIndex(description); loc != nil {
+		if url := mdURL.FindString(description[loc[0]:]); url != "" {
+			return url
+		}
+	}
+	return mdURL.FindString(description)
+}
@@ -2,11 +2,15 @@ package ui
 
 import (
 	"context"
-	"sort"
-	"strings"
 	"time"
 
+	"polyterm/alerts"
 	"polyterm/api"
+	"polyterm/config"
+	"polyterm/metrics"
+	"polyterm/search"
+	"polyterm/session"
+	"polyterm/store"
 	"polyterm/types"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -21,6 +25,7 @@ const (
 	viewList viewMode = iota
 	viewDetail
 	viewStats
+	viewCompare
 )
 
 type pageMode int
@@ -28,24 +33,9 @@ type pageMode int
 const (
 	pageMarkets pageMode = iota
 	pageStats
-)
-
-type sortMode int
-
-const (
-	sortVolume sortMode = iota
-	sortChange
-	sortLiquidity
-)
-
-type filterMode int
-
-const (
-	filterAll filterMode = iota
-	filterCrypto
-	filterPolitics
-	filterSports
-	filterEntertainment
+	pageCategories
+	pageArbitrage
+	pageResolved
 )
 
 type Model struct {
@@ -53,6 +43,13 @@ type Model struct {
 	loading         bool
 	markets         []types.Market
 	filteredMarkets []types.Market
+	index           *search.Index
+	store           *store.Store
+	signals         map[string]metrics.Signals
+	changes         changeState
+	selection       selectionState
+	clipboard       string
+	copiedAt        time.Time
 	stats           types.GlobalStats
 	err             error
 	width           int
@@ -61,18 +58,77 @@ type Model struct {
 	cursor          int
 	maxDisplay      int
 	lastUpdate      time.Time
+	lastClick       time.Time
+	lastClickRow    int
 	autoRefresh     bool
 	currentView     viewMode
 	currentPage     pageMode
-	selectedMarket  int
+	selectedID      string
+	selected        types.Market
+	compare         []types.Market
+	splitHidden     bool
+	desc            descState
+	detailQuery     string
+	pendingOpen     *api.MarketRef
 	ready           bool
 	searchMode      bool
 	searchQuery     string
-	sortBy          sortMode
-	filterBy        filterMode
+	sortBy          api.SortMode
+	filterBy        api.FilterMode
+	categoryFilter  string
+	cfg             config.Config
+	alerts          *alerts.Engine
+	panels          []analyticsPanel
+	panelRows       []panelRow
+	panelFocus      int
+	categoryCursor  int
+	categoryScroll  int
+	categorySort    categorySort
+	categoryTreemap bool
+	arbGroups       []types.ArbGroup
+	arbErr          error
+	arbScanning     bool
+	arbCursor       int
+	arbScroll       int
+	arbAll          bool
+	resolved        []types.Market
+	resolvedIndex   *search.Index
+	resolvedView    []int
+	resolvedErr     error
+	resolvedLoading bool
+	resolvedQuery   string
+	resolvedCursor  int
+	resolvedScroll  int
+	resolvedRange   int
+	resolvedFrom    time.Time
+	resolvedTo      time.Time
+	cache           *api.Cache
+	recorder        *session.Recorder
+	replay          replayState
+	palette         paletteState
+	keys            keyMap
+	showHelp        bool
+	status          string
+	statusErr       bool
 }
 
-func NewModel() Model {
+func NewModel(cfg config.Config, st *store.Store) (Model, error) {
+	keys, err := newKeyMap(cfg.Keymap)
+	if err != nil {
+		return Model{}, err
+	}
+
+	theme, err := resolveTheme(cfg.Theme, cfg.Themes)
+	if err != nil {
+		return Model{}, err
+	}
+	applyTheme(theme)
+
+	panels, panelRows, err := newDashboard(cfg.Dashboard)
+	if err != nil {
+		return Model{}, err
+	}
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = LoadingStyle
@@ -85,27 +141,46 @@ func NewModel() Model {
 		autoRefresh:     true,
 		currentView:     viewList,
 		currentPage:     pageMarkets,
-		selectedMarket:  -1,
 		ready:           false,
 		searchMode:      false,
 		searchQuery:     "",
-		sortBy:          sortVolume,
-		filterBy:        filterAll,
+		sortBy:          api.SortVolume,
+		filterBy:        api.FilterAll,
 		filteredMarkets: []types.Market{},
-	}
+		cfg:             cfg,
+		alerts:          alerts.NewEngine(cfg.Alerts),
+		store:           st,
+		keys:            keys,
+		panels:          panels,
+		panelRows:       panelRows,
+		resolvedRange:   len(resolvedRanges) - 1,
+		cache:           api.NewCache(0, nil),
+	}, nil
+}
+
+func (m Model) WithCache(c *api.Cache) Model {
+	m.cache = c
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
+	if m.replaying() {
+		return tea.Batch(m.spinner.Tick, replayTickCmd())
+	}
+	cmds := []tea.Cmd{
 		m.spinner.Tick,
-		fetchMarketsCmd(500),
+		fetchMarketsCmd(m.cache, 500),
 		tickCmd(),
-	)
+	}
+	if m.store != nil {
+		cmds = append(cmds, resolveCmd(m.store))
+	}
+	return tea.Batch(cmds...)
 }
 
-func fetchMarketsCmd(limit int) tea.Cmd {
+func fetchMarketsCmd(cache *api.Cache, limit int) tea.Cmd {
 	return func() tea.Msg {
-		markets, stats, err := api.FetchMarkets(context.Background(), limit)
+		markets, stats, err := cache.Markets(context.Background(), limit)
 		return types.FetchResult{Markets: markets, Stats: stats, Err: err}
 	}
 }
@@ -117,98 +192,17 @@ func tickCmd() tea.Cmd {
 }
 
 func (m *Model) applyFiltersAndSort() {
-	filtered := make([]types.Market, 0)
-	
-	for _, market := range m.markets {
-		vol := market.GetVolume()
-		if vol < 100 && market.Volume24hr < 10 {
-			continue
-		}
-		
-		if m.filterBy != filterAll {
-			category := strings.ToLower(market.Category)
-			question := strings.ToLower(market.Question)
-			
-			match := false
-			switch m.filterBy {
-			case filterCrypto:
-				match = strings.Contains(category, "crypto") || 
-					strings.Contains(question, "bitcoin") ||
-					strings.Contains(question, "ethereum") ||
-					strings.Contains(question, "crypto")
-			case filterPolitics:
-				match = strings.Contains(category, "politics") ||
-					strings.Contains(question, "election") ||
-					strings.Contains(question, "president") ||
-					strings.Contains(question, "congress") ||
-					strings.Contains(question, "senate") ||
-					strings.Contains(question, "mayor") ||
-					strings.Contains(question, "governor")
-			case filterSports:
-				match = strings.Contains(category, "sports") ||
-					strings.Contains(question, "nba") ||
-					strings.Contains(question, "nfl") ||
-					strings.Contains(question, "fifa") ||
-					strings.Contains(question, "champion") ||
-					strings.Contains(question, "world series") ||
-					strings.Contains(question, "playoff")
-			case filterEntertainment:
-				match = strings.Contains(category, "entertainment") ||
-					strings.Contains(question, "movie") ||
-					strings.Contains(question, "oscar") ||
-					strings.Contains(question, "box office")
+	id := m.cursorID()
+	m.filteredMarkets = api.Listing(m.markets, m.index, m.searchQuery, m.filterBy, m.sortBy)
+	if m.categoryFilter != "" {
+		filtered := m.filteredMarkets[:0]
+		for _, market := range m.filteredMarkets {
+			if market.GetCategory() == m.categoryFilter {
+				filtered = append(filtered, market)
 			}
-			
-			if !match {
-				continue
-			}
-		}
-		
-		if m.searchQuery != "" {
-			query := strings.ToLower(m.searchQuery)
-			question := strings.ToLower(market.Question)
-			description := strings.ToLower(market.Description)
-			
-			if !strings.Contains(question, query) && !strings.Contains(description, query) {
-				continue
-			}
-		}
-		
-		filtered = append(filtered, market)
-	}
-	
-	switch m.sortBy {
-	case sortVolume:
-		sort.Slice(filtered, func(i, j int) bool {
-			volI := filtered[i].GetVolume()
-			volJ := filtered[j].GetVolume()
-			if volI == 0 && volJ == 0 {
-				return filtered[i].Volume24hr > filtered[j].Volume24hr
-			}
-			return volI > volJ
-		})
-	case sortChange:
-		sort.Slice(filtered, func(i, j int) bool {
-			return filtered[i].OneDayPriceChange > filtered[j].OneDayPriceChange
-		})
-	case sortLiquidity:
-		sort.Slice(filtered, func(i, j int) bool {
-			return filtered[i].GetLiquidity() > filtered[j].GetLiquidity()
-		})
-	}
-	
-	m.filteredMarkets = filtered
-	
-	if m.cursor >= len(filtered) {
-		m.cursor = len(filtered) - 1
-		if m.cursor < 0 {
-			m.cursor = 0
-		}
-	}
-	if m.scroll >= len(filtered) {
-		m.scroll = len(filtered) - m.maxDisplay
-		if m.scroll < 0 {
-			m.scroll = 0
 		}
+		m.filteredMarkets = filtered
 	}
+	m.followCursor(id)
+	m.pinSelected()
 }
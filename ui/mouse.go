@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const doubleClickInterval = 400 * time.Millisecond
+
+type span struct {
+	x0, x1 int
+}
+
+func (s span) contains(x int) bool {
+	return x >= s.x0 && x < s.x1
+}
+
+type hitLayout struct {
+	tabsY     int
+	tabs      []span
+	headerY   int
+	headerH   int
+	columns   []span
+	rowsY     int
+	rowsShown int
+	rowsX1    int
+	hasTable  bool
+}
+
+func (m Model) layout() hitLayout {
+	var l hitLayout
+	var blocks []string
+	var tabsIdx, tableIdx int
+
+	switch m.currentPage {
+	case pageMarkets:
+		blocks, tabsIdx, tableIdx = m.marketsPageBlocks()
+		l.hasTable = true
+	case pageCategories:
+		blocks, tabsIdx = m.categoriesPageBlocks()
+	case pageArbitrage:
+		blocks, tabsIdx = m.arbitragePageBlocks()
+	case pageResolved:
+		blocks, tabsIdx = m.resolvedPageBlocks()
+	default:
+		blocks, tabsIdx = m.statsPageBlocks()
+	}
+
+	offsets := make([]int, len(blocks))
+	total := 0
+	for i, block := range blocks {
+		offsets[i] = total
+		total += lipgloss.Height(block)
+	}
+
+	top := 0
+	if m.height > 0 && total > m.height {
+		top = total - m.height
+	}
+
+	l.tabsY = offsets[tabsIdx] - top
+	x := 0
+	for i, tab := range m.tabLabels() {
+		if i > 0 {
+			x++
+		}
+		w := lipgloss.Width(tab)
+		l.tabs = append(l.tabs, span{x, x + w})
+		x += w
+	}
+
+	if l.hasTable {
+		widths, headers := m.tableHeaders()
+		header := renderHeaderRow(headers, widths)
+
+		l.headerY = offsets[tableIdx] - top
+		l.headerH = lipgloss.Height(header)
+		l.rowsY = l.headerY + l.headerH
+		l.rowsShown = min(m.maxDisplay, m.listLen()-m.scroll)
+
+		x = 0
+		for i := range headers {
+			if i > 0 {
+				x++
+			}
+			w := lipgloss.Width(renderHeaderCell(headers[i], widths[i]))
+			l.columns = append(l.columns, span{x, x + w})
+			x += w
+		}
+		if m.splitLayout() {
+			l.rowsX1 = x
+		}
+	}
+
+	return l
+}
+
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.currentView == viewDetail {
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.desc.view.ScrollUp(3)
+		case tea.MouseButtonWheelDown:
+			m.desc.view.ScrollDown(3)
+		}
+		return m, nil
+	}
+	if m.currentView != viewList {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		actionMoveUp(&m, "")
+		return m, nil
+	case tea.MouseButtonWheelDown:
+		actionMoveDown(&m, "")
+		return m, nil
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+	default:
+		return m, nil
+	}
+
+	l := m.layout()
+
+	if msg.Y == l.tabsY {
+		for i, tab := range l.tabs {
+			if tab.contains(msg.X) {
+				return m, m.setPage(pageTabs[i].page)
+			}
+		}
+	}
+
+	if !l.hasTable {
+		return m, nil
+	}
+
+	if msg.Y >= l.headerY && msg.Y < l.headerY+l.headerH {
+		for i, col := range l.columns {
+			if col.contains(msg.X) && marketColumns[i].sortable {
+				m.sortBy = marketColumns[i].sort
+				m.applyFiltersAndSort()
+				return m, nil
+			}
+		}
+		return m, nil
+	}
+
+	if msg.Y >= l.rowsY && msg.Y < l.rowsY+l.rowsShown && (l.rowsX1 == 0 || msg.X < l.rowsX1) {
+		row := m.scroll + msg.Y - l.rowsY
+		now := time.Now()
+		double := row == m.lastClickRow && now.Sub(m.lastClick) < doubleClickInterval
+
+		m.cursor = row
+		m.lastClick = now
+		m.lastClickRow = row
+
+		if double {
+			m.lastClick = time.Time{}
+			actionOpenDetail(&m, "")
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"polyterm/search"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxPaletteHistory = 8
+
+type paletteEntry struct {
+	action *action
+	arg    string
+}
+
+type paletteState struct {
+	open    bool
+	query   string
+	cursor  int
+	prompt  *action
+	arg     string
+	history []paletteEntry
+}
+
+func (e paletteEntry) label() string {
+	if e.arg == "" {
+		return e.action.title
+	}
+	return fmt.Sprintf("%s %s", strings.TrimSuffix(e.action.title, "…"), e.arg)
+}
+
+func (m *Model) openPalette() {
+	m.palette.open = true
+	m.palette.query = ""
+	m.palette.cursor = 0
+	m.palette.prompt = nil
+	m.palette.arg = ""
+}
+
+func (m *Model) closePalette() {
+	m.palette.open = false
+	m.palette.prompt = nil
+}
+
+func (m *Model) paletteItems() []paletteEntry {
+	type scored struct {
+		entry paletteEntry
+		score int
+	}
+
+	var items []scored
+	seen := make(map[string]bool)
+	for i, e := range m.palette.history {
+		if score, ok := search.Fuzzy(m.palette.query, e.label()); ok {
+			items = append(items, scored{e, score + maxPaletteHistory - i})
+			if e.arg == "" {
+				seen[e.action.id] = true
+			}
+		}
+	}
+	for i := range actions {
+		a := &actions[i]
+		if a.hidden || seen[a.id] || ((a.scope == scopeDetail || a.scope == scopeReplay) && !m.inScope(a.scope)) {
+			continue
+		}
+		if score, ok := search.Fuzzy(m.palette.query, a.title); ok {
+			items = append(items, scored{paletteEntry{action: a}, score})
+		}
+	}
+
+	if m.palette.query != "" {
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].score > items[j].score
+		})
+	}
+
+	entries := make([]paletteEntry, len(items))
+	for i, it := range items {
+		entries[i] = it.entry
+	}
+	return entries
+}
+
+func (m *Model) runPaletteEntry(e paletteEntry) tea.Cmd {
+	m.closePalette()
+	m.status = ""
+
+	history := []paletteEntry{e}
+	for _, h := range m.palette.history {
+		if h.action != e.action || h.arg != e.arg {
+			history = append(history, h)
+		}
+	}
+	if len(history) > maxPaletteHistory {
+		history = history[:maxPaletteHistory]
+	}
+	m.palette.history = history
+
+	m.enterScope(e.action.scope)
+	return e.action.run(m, e.arg)
+}
+
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.palette.prompt != nil {
+		switch msg.String() {
+		case "esc":
+			m.palette.prompt = nil
+			m.palette.arg = ""
+		case "enter":
+			cmd := m.runPaletteEntry(paletteEntry{action: m.palette.prompt, arg: m.palette.arg})
+			return m, cmd
+		case "backspace":
+			if len(m.palette.arg) > 0 {
+				m.palette.arg = m.palette.arg[:len(m.palette.arg)-1]
+			}
+		case "ctrl+u":
+			m.palette.arg = ""
+		default:
+			if len(msg.String()) == 1 {
+				m.palette.arg += msg.String()
+			}
+		}
+		return m, nil
+	}
+
+	items := m.paletteItems()
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.closePalette()
+	case "enter":
+		if m.palette.cursor < len(items) {
+			e := items[m.palette.cursor]
+			if e.arg == "" && e.action.prompt != nil {
+				m.palette.prompt = e.action
+				m.palette.arg = ""
+				return m, nil
+			}
+			cmd := m.runPaletteEntry(e)
+			return m, cmd
+		}
+	case "up", "ctrl+k":
+		if m.palette.cursor > 0 {
+			m.palette.cursor--
+		}
+	case "down", "ctrl+j", "ctrl+n":
+		if m.palette.cursor < len(items)-1 {
+			m.palette.cursor++
+		}
+	case "backspace":
+		if len(m.palette.query) > 0 {
+			m.palette.query = m.palette.query[:len(m.palette.query)-1]
+			m.palette.cursor = 0
+		}
+	case "ctrl+u":
+		m.palette.query = ""
+		m.palette.cursor = 0
+	default:
+		if len(msg.String()) == 1 {
+			m.palette.query += msg.String()
+			m.palette.cursor = 0
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderPalette() string {
+	width := m.width - 8
+	if width > 80 {
+		width = 80
+	}
+	if width < 40 {
+		width = 40
+	}
+
+	promptStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true)
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(colorSelectionText).
+		Background(colorSelection).
+		Bold(true).
+		Width(width - 4)
+	itemStyle := lipgloss.NewStyle().Foreground(colorSubtle).Width(width - 4)
+
+	var lines []string
+	if m.palette.prompt != nil {
+		lines = append(lines,
+			promptStyle.Render(m.palette.prompt.title),
+			MutedStyle.Render(m.palette.prompt.prompt(&m)),
+			"",
+			promptStyle.Render("> ")+m.palette.arg+"_",
+			"",
+			MutedStyle.Render("enter: run | esc: back"),
+		)
+	} else {
+		lines = append(lines, promptStyle.Render(": ")+m.palette.query+"_", "")
+
+		items := m.paletteItems()
+		maxItems := m.height - 14
+		if maxItems < 5 {
+			maxItems = 5
+		}
+		start := 0
+		if m.palette.cursor >= maxItems {
+			start = m.palette.cursor - maxItems + 1
+		}
+		end := min(start+maxItems, len(items))
+
+		if len(items) == 0 {
+			lines = append(lines, MutedStyle.Render("No matching commands"))
+		}
+		for i := start; i < end; i++ {
+			e := items[i]
+			label := e.label()
+			if b := m.keys.binding(e.action.id); b.Enabled() && e.arg == "" {
+				label = fmt.Sprintf("%-44s %s", label, b.Help().Key)
+			}
+			if i < len(m.palette.history) && m.palette.query == "" {
+				label = "↺ " + label
+			} else {
+				label = "  " + label
+			}
+
+			if i == m.palette.cursor {
+				lines = append(lines, selectedStyle.Render(label))
+			} else {
+				lines = append(lines, itemStyle.Render(label))
+			}
+		}
+		lines = append(lines, "", MutedStyle.Render("type to filter | ↑/↓: select | enter: run | esc: close"))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorSecondary).
+		Padding(0, 1).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		m.renderHeader(),
+		m.renderTabs(),
+		"",
+		box,
+	)
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"polyterm/config"
+	"polyterm/session"
+	"polyterm/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	replayInterval = 100 * time.Millisecond
+	replaySeekStep = 5 * time.Minute
+	replayBarWidth = 20
+)
+
+var replaySpeeds = []float64{1, 2, 5, 10, 25, 50, 100}
+
+type replayTickMsg time.Time
+
+type replayState struct {
+	frames  []session.Frame
+	pos     int
+	clock   time.Time
+	playing bool
+	speed   float64
+}
+
+func (r replayState) start() time.Time {
+	return r.frames[0].At
+}
+
+func (r replayState) end() time.Time {
+	return r.frames[len(r.frames)-1].At
+}
+
+func NewReplayModel(cfg config.Config, frames []session.Frame, speed float64, playing bool) (Model, error) {
+	if len(frames) == 0 {
+		return Model{}, errors.New("recording has no frames")
+	}
+	m, err := NewModel(cfg, nil)
+	if err != nil {
+		return Model{}, err
+	}
+	m.autoRefresh = false
+	m.replay = replayState{frames: frames, playing: playing, speed: speed}
+	m.seekReplay(frames[0].At)
+	return m, nil
+}
+
+func (m Model) WithRecorder(r *session.Recorder) Model {
+	m.recorder = r
+	return m
+}
+
+func (m Model) replaying() bool {
+	return len(m.replay.frames) > 0
+}
+
+func (m *Model) record(msg tea.Msg) {
+	if m.recorder == nil {
+		return
+	}
+	if err := m.recorder.Record(time.Now(), msg); err != nil {
+		m.setError(err)
+	}
+}
+
+func replayTickCmd() tea.Cmd {
+	return tea.Tick(replayInterval, func(t time.Time) tea.Msg {
+		return replayTickMsg(t)
+	})
+}
+
+func (m *Model) advanceReplay() tea.Cmd {
+	r := &m.replay
+	var cmd tea.Cmd
+	if r.playing {
+		cmd = m.seekReplay(r.clock.Add(time.Duration(float64(replayInterval) * r.speed)))
+		if !r.clock.Before(r.end()) {
+			r.playing = false
+			m.setStatus("End of recording")
+		}
+	}
+	return tea.Batch(cmd, replayTickCmd())
+}
+
+func (m *Model) seekReplay(at time.Time) tea.Cmd {
+	r := &m.replay
+	if at.Before(r.start()) {
+		at = r.start()
+	}
+	if at.After(r.end()) {
+		at = r.end()
+	}
+	r.clock = at
+
+	pos := sort.Search(len(r.frames), func(i int) bool { return r.frames[i].At.After(at) })
+	if pos == r.pos {
+		return nil
+	}
+	from := r.pos
+	if pos < r.pos {
+		from = 0
+	}
+	r.pos = pos
+
+	var cmd tea.Cmd
+	fetch, arb := -1, -1
+	for i := pos - 1; i >= from && (fetch < 0 || arb < 0); i-- {
+		switch {
+		case r.frames[i].Kind == session.KindFetch && fetch < 0:
+			fetch = i
+		case r.frames[i].Kind == session.KindArbitrage && arb < 0:
+			arb = i
+		}
+	}
+	if fetch >= 0 {
+		cmd = m.applyFetchResult(r.frames[fetch].Msg().(types.FetchResult), r.frames[fetch].At)
+	}
+	if arb >= 0 {
+		m.applyArbitrageResult(r.frames[arb].Msg().(types.ArbitrageResult))
+	} else if from == 0 {
+		m.arbGroups = nil
+		m.moveArbCursor(0)
+	}
+	return cmd
+}
+
+func parseSeek(s string, r replayState) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return time.Time{}, errors.New("empty seek target")
+	case strings.HasSuffix(s, "%"):
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return time.Time{}, fmt.Errorf("invalid percentage %q", s)
+		}
+		return r.start().Add(time.Duration(float64(r.end().Sub(r.start())) * pct / 100)), nil
+	case s[0] == '+' || s[0] == '-':
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid offset %q (e.g. +5m or -30s)", s)
+		}
+		return r.clock.Add(d), nil
+	}
+
+	start := r.start().Local()
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "15:04:05", "15:04"} {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			continue
+		}
+		if !strings.HasPrefix(layout, "2006") {
+			t = time.Date(start.Year(), start.Month(), start.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local)
+			if t.Before(start.Truncate(time.Second)) {
+				t = t.AddDate(0, 0, 1)
+			}
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid seek target %q (use HH:MM[:SS], YYYY-MM-DD HH:MM, +5m/-30s or 50%%)", s)
+}
+
+func (m Model) replayStatus() (clock, state string) {
+	r := m.replay
+	icon := "⏸"
+	if r.playing {
+		icon = "▶"
+	}
+
+	progress := 1.0
+	if total := r.end().Sub(r.start()); total > 0 {
+		progress = float64(r.clock.Sub(r.start())) / float64(total)
+	}
+	filled := int(progress * replayBarWidth)
+	bar := lipgloss.NewStyle().Foreground(colorAccent).Render(strings.Repeat("█", filled)) +
+		MutedStyle.Render(strings.Repeat("░", replayBarWidth-filled))
+
+	replayStyle := lipgloss.NewStyle().Foreground(colorSecondary).Bold(true)
+	clock = replayStyle.Render("REPLAY ") + MutedStyle.Render(r.clock.Local().Format("Jan 02 15:04:05"))
+	state = replayStyle.Render(fmt.Sprintf("%s %gx", icon, r.speed)) + " " + bar +
+		MutedStyle.Render(fmt.Sprintf(" %d/%d", r.pos, len(r.frames)))
+	return clock, state
+}
+
+func actionToggleReplay(m *Model, _ string) tea.Cmd {
+	r := &m.replay
+	var cmd tea.Cmd
+	if !r.playing && !r.clock.Before(r.end()) {
+		cmd = m.seekReplay(r.start())
+	}
+	r.playing = !r.playing
+	return cmd
+}
+
+func actionReplaySpeed(delta int) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		speed := m.replay.speed
+		for i := range replaySpeeds {
+			s := replaySpeeds[i]
+			if delta < 0 {
+				s = replaySpeeds[len(replaySpeeds)-1-i]
+			}
+			if (delta > 0 && s > speed) || (delta < 0 && s < speed) {
+				m.replay.speed = s
+				break
+			}
+		}
+		m.setStatus("Replay speed %gx", m.replay.speed)
+		return nil
+	}
+}
+
+func actionReplayStep(delta int) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		r := &m.replay
+		idx := max(0, min(len(r.frames)-1, r.pos-1+delta))
+		return m.seekReplay(r.frames[idx].At)
+	}
+}
+
+func actionReplaySeekBy(d time.Duration) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		return m.seekReplay(m.replay.clock.Add(d))
+	}
+}
+
+func actionReplaySeek(m *Model, arg string) tea.Cmd {
+	at, err := parseSeek(arg, m.replay)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+	return m.seekReplay(at)
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"polyterm/api"
+	"polyterm/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const resolveEvery = time.Hour
+
+type resolveTickMsg time.Time
+
+type resolveResult struct {
+	added int
+	err   error
+}
+
+func resolveTickCmd() tea.Cmd {
+	return tea.Tick(resolveEvery, func(t time.Time) tea.Msg {
+		return resolveTickMsg(t)
+	})
+}
+
+func resolveCmd(st *store.Store) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		ids := st.Unresolved(now)
+		markets, fetchErr := api.FetchMarketsByID(context.Background(), ids)
+		added, err := st.Resolve(markets)
+		if fetchErr == nil && err == nil {
+			err = st.Checked(ids, now)
+		}
+		return resolveResult{len(added), errors.Join(fetchErr, err, st.Prune(now))}
+	}
+}
+
+func (m *Model) applyResolveResult(msg resolveResult) tea.Cmd {
+	if msg.err != nil {
+		m.setError(fmt.Errorf("checking resolutions: %w", msg.err))
+	} else if msg.added > 0 {
+		m.setStatus("Recorded %d new market resolutions for calibration", msg.added)
+	}
+	return resolveTickCmd()
+}
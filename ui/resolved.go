@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"polyterm/api"
+	"polyterm/search"
+	"polyterm/types"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	resolvedLimit       = 500
+	resolvedDetailLines = 7
+	resolvedMinWidth    = 20
+	sparkWidth          = 16
+	rangeLayout         = "2006-01-02"
+)
+
+var resolvedRanges = []struct {
+	label string
+	days  int
+}{
+	{"7 days", 7},
+	{"30 days", 30},
+	{"90 days", 90},
+	{"All", 0},
+}
+
+var resolvedColumns = []tableColumn{
+	{title: "Closed", width: 11},
+	{title: "Market"},
+	{title: "Winner", width: 12},
+	{title: "Trajectory", width: sparkWidth},
+	{title: "Volume", width: 10},
+}
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+func fetchClosedCmd(limit int) tea.Cmd {
+	return func() tea.Msg {
+		markets, err := api.FetchClosedMarkets(context.Background(), limit)
+		return types.ClosedResult{Markets: markets, Err: err}
+	}
+}
+
+func (m *Model) loadResolved() tea.Cmd {
+	if m.resolvedLoading {
+		return nil
+	}
+	if m.replaying() {
+		m.resolvedErr = errors.New("closed markets are not available in replay")
+		return nil
+	}
+	m.resolvedLoading = true
+	m.resolvedErr = nil
+	return tea.Batch(m.spinner.Tick, fetchClosedCmd(resolvedLimit))
+}
+
+func (m *Model) setResolved(markets []types.Market) {
+	m.resolved = markets
+	m.resolvedIndex = search.NewIndex(markets)
+	m.applyResolvedFilter()
+}
+
+func (m Model) resolvedWindow() (from, to time.Time) {
+	if m.resolvedRange < 0 {
+		return m.resolvedFrom, m.resolvedTo
+	}
+	if days := resolvedRanges[m.resolvedRange].days; days > 0 {
+		from = time.Now().AddDate(0, 0, -days)
+	}
+	return from, time.Time{}
+}
+
+func (m Model) resolvedRangeLabel() string {
+	if m.resolvedRange >= 0 {
+		return resolvedRanges[m.resolvedRange].label
+	}
+	from, to := "…", "…"
+	if !m.resolvedFrom.IsZero() {
+		from = m.resolvedFrom.Format(rangeLayout)
+	}
+	if !m.resolvedTo.IsZero() {
+		to = m.resolvedTo.AddDate(0, 0, -1).Format(rangeLayout)
+	}
+	return from + ".." + to
+}
+
+func (m *Model) applyResolvedFilter() {
+	from, to := m.resolvedWindow()
+	inRange := func(i int) bool {
+		closed := api.CloseTime(&m.resolved[i])
+		return (from.IsZero() || !closed.Before(from)) && (to.IsZero() || closed.Before(to))
+	}
+
+	m.resolvedView = nil
+	if m.resolvedQuery != "" && m.resolvedIndex != nil {
+		for _, r := range m.resolvedIndex.Search(m.resolvedQuery) {
+			if inRange(r.Index) {
+				m.resolvedView = append(m.resolvedView, r.Index)
+			}
+		}
+	} else {
+		for i := range m.resolved {
+			if inRange(i) {
+				m.resolvedView = append(m.resolvedView, i)
+			}
+		}
+	}
+
+	m.resolvedCursor = max(0, min(m.resolvedCursor, len(m.resolvedView)-1))
+	m.moveResolvedCursor(0)
+}
+
+func parseRange(s string) (from, to time.Time, err error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "d") {
+		var days int
+		if _, err := fmt.Sscanf(s, "%dd", &days); err == nil && days > 0 {
+			return time.Now().AddDate(0, 0, -days), time.Time{}, nil
+		}
+	}
+
+	start, end, ok := strings.Cut(s, "..")
+	if !ok {
+		end = start
+	}
+	if start = strings.TrimSpace(start); start != "" {
+		if from, err = time.ParseInLocation(rangeLayout, start, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", start)
+		}
+	}
+	if end = strings.TrimSpace(end); end != "" {
+		if to, err = time.ParseInLocation(rangeLayout, end, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", end)
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if from.IsZero() && to.IsZero() {
+		return from, to, fmt.Errorf("empty date range")
+	}
+	return from, to, nil
+}
+
+func (m Model) resolvedRows() int {
+	chrome := 11 + resolvedDetailLines
+	if m.status != "" {
+		chrome++
+	}
+	return max(3, m.height-chrome)
+}
+
+func (m *Model) moveResolvedCursor(delta int) {
+	n := len(m.resolvedView)
+	if n == 0 {
+		m.resolvedCursor, m.resolvedScroll = 0, 0
+		return
+	}
+	m.resolvedCursor = max(0, min(n-1, m.resolvedCursor+delta))
+
+	rows := m.resolvedRows()
+	if m.resolvedCursor < m.resolvedScroll {
+		m.resolvedScroll = m.resolvedCursor
+	}
+	if m.resolvedCursor >= m.resolvedScroll+rows {
+		m.resolvedScroll = m.resolvedCursor - rows + 1
+	}
+	m.resolvedScroll = max(0, min(m.resolvedScroll, n-1))
+}
+
+func winnerText(market *types.Market) string {
+	winner, ok := api.Winner(market)
+	if !ok {
+		return "Unresolved"
+	}
+	if outcomes := market.GetOutcomes(); winner < len(outcomes) {
+		return outcomes[winner]
+	}
+	return "Outcome " + fmt.Sprint(winner+1)
+}
+
+func (m Model) trajectory(market *types.Market) (points []float64, label string) {
+	if m.store != nil {
+		if history := m.store.History(market.ID); len(history) >= 2 {
+			for _, p := range history {
+				points = append(points, p.Yes)
+			}
+			return points, fmt.Sprintf("%d stored snapshots", len(history))
+		}
+	}
+
+	last := market.LastTradePrice
+	for _, change := range []float64{market.OneMonthPriceChange, market.OneWeekPriceChange, market.OneDayPriceChange, market.OneHourPriceChange} {
+		points = append(points, max(0, min(1, last-change)))
+	}
+	return append(points, last), "1mo, 1w, 1d, 1h before close and last trade"
+}
+
+func sparkline(values []float64, width int) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+	out := make([]rune, width)
+	for i := range out {
+		v := values[0]
+		if width > 1 {
+			v = values[i*(len(values)-1)/(width-1)]
+		}
+		out[i] = sparkRunes[max(0, min(len(sparkRunes)-1, int(v*float64(len(sparkRunes)))))]
+	}
+	return string(out)
+}
+
+func (m Model) renderResolved() string {
+	searchStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true)
+	search := MutedStyle.Render("Search: ") + MutedStyle.Render("-")
+	if m.searchMode {
+		search = searchStyle.Render("Search: ") + m.resolvedQuery + "_"
+	} else if m.resolvedQuery != "" {
+		search = MutedStyle.Render("Search: ") + searchStyle.Render(m.resolvedQuery)
+	}
+	bar := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		MutedStyle.Render("Closed: "), lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render(m.resolvedRangeLabel()),
+		"  ", search,
+		MutedStyle.Render(fmt.Sprintf("  Results: %d of %d", len(m.resolvedView), len(m.resolved))),
+	)
+	if m.resolvedLoading {
+		bar += "  " + m.spinner.View() + MutedStyle.Render(" loading closed markets…")
+	}
+
+	lines := []string{bar, ""}
+	if m.resolvedErr != nil {
+		lines = append(lines, ErrorStyle.Render("Error: "+truncate(m.resolvedErr.Error(), max(20, m.width-8))))
+	}
+	if len(m.resolvedView) == 0 {
+		if !m.resolvedLoading {
+			lines = append(lines, MutedStyle.Render("No closed markets match"))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, m.renderResolvedTable())
+	lines = append(lines, "", m.renderResolvedDetail(&m.resolved[m.resolvedView[m.resolvedCursor]]))
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderResolvedTable() string {
+	widths := make([]int, len(resolvedColumns))
+	headers := make([]string, len(resolvedColumns))
+	used := 0
+	for i, col := range resolvedColumns {
+		widths[i] = col.width
+		headers[i] = col.title
+		used += col.width + 3
+	}
+	widths[1] = max(resolvedMinWidth, m.width-used-3)
+
+	rows := []string{renderHeaderRow(headers, widths)}
+	visible := m.resolvedRows()
+	end := min(len(m.resolvedView), m.resolvedScroll+visible)
+	for i := m.resolvedScroll; i < end; i++ {
+		market := &m.resolved[m.resolvedView[i]]
+		points, _ := m.trajectory(market)
+		cells := []string{
+			formatDate(api.CloseTime(market)),
+			truncate(market.Question, widths[1]),
+			truncate(winnerText(market), widths[2]),
+			sparkline(points, sparkWidth),
+			formatCurrency(market.GetVolume()),
+		}
+
+		style := TableCellStyle
+		if i == m.resolvedCursor {
+			style = SelectedRowStyle
+		} else if i%2 == 0 {
+			style = StripedRowStyle
+		}
+
+		formatted := make([]string, len(cells))
+		for j, cell := range cells {
+			formatted[j] = style.Render(cell + strings.Repeat(" ", max(0, widths[j]-lipgloss.Width(cell))))
+		}
+		rows = append(rows, joinCells(formatted))
+	}
+
+	if len(m.resolvedView) > visible {
+		rows = append(rows, MutedStyle.Render(fmt.Sprintf("Showing %d-%d of %d markets", m.resolvedScroll+1, end, len(m.resolvedView))))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m Model) renderResolvedDetail(market *types.Market) string {
+	titleStyle := lipgloss.NewStyle().Foreground(colorSecondary).Bold(true)
+	winnerStyle := lipgloss.NewStyle().Foreground(colorPositive).Bold(true)
+
+	points, source := m.trajectory(market)
+	path := make([]string, len(points))
+	for i, p := range points {
+		path[i] = fmt.Sprintf("%.0f%%", p*100)
+	}
+	if len(path) > 8 {
+		path = append(path[:4], append([]string{"…"}, path[len(path)-3:]...)...)
+	}
+
+	width := max(resolvedMinWidth, m.width-4)
+	return strings.Join([]string{
+		titleStyle.Render(truncate(market.Question, width)),
+		MutedStyle.Render("Winner: ") + winnerStyle.Render(winnerText(market)) +
+			MutedStyle.Render(fmt.Sprintf("  Resolved: %s  Volume: %s  Category: %s", formatTime(api.CloseTime(market)), formatCurrency(market.GetVolume()), market.GetCategory())),
+		MutedStyle.Render("Trajectory (YES): ") + lipgloss.NewStyle().Foreground(colorAccent).Render(sparkline(points, min(len(points)*2, max(sparkWidth, width/2)))) + " " + strings.Join(path, " → "),
+		MutedStyle.Render("  from " + source),
+		MutedStyle.Render(truncate(strings.ReplaceAll(market.Description, "\n", " "), width)),
+	}, "\n")
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "N/A"
+	}
+	return t.Local().Format("Jan 02 2006")
+}
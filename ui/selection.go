@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"polyterm/config"
+	"polyterm/types"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
+)
+
+const clipboardHold = 250 * time.Millisecond
+
+type clipboardSentMsg time.Time
+
+type selectionState struct {
+	marked map[string]bool
+	visual bool
+	anchor string
+}
+
+func (m Model) visualRange() (lo, hi int, ok bool) {
+	if !m.selection.visual {
+		return 0, 0, false
+	}
+	a := marketIndex(m.displayMarkets(), m.selection.anchor)
+	if a < 0 {
+		return 0, 0, false
+	}
+	return min(a, m.cursor), max(a, m.cursor), true
+}
+
+func (m Model) isMarked(i int, id string) bool {
+	if m.selection.marked[id] {
+		return true
+	}
+	lo, hi, ok := m.visualRange()
+	return ok && i >= lo && i <= hi
+}
+
+func (m Model) markedMarkets() []types.Market {
+	var out []types.Market
+	display := m.displayMarkets()
+	for i := range display {
+		if m.isMarked(i, display[i].ID) {
+			out = append(out, display[i])
+		}
+	}
+	for i := range m.markets {
+		if m.selection.marked[m.markets[i].ID] && marketIndex(display, m.markets[i].ID) < 0 {
+			out = append(out, m.markets[i])
+		}
+	}
+	return out
+}
+
+func (m *Model) targetMarkets() []types.Market {
+	if marked := m.markedMarkets(); len(marked) > 0 {
+		return marked
+	}
+	if market := m.currentMarket(); market != nil {
+		return []types.Market{*market}
+	}
+	return nil
+}
+
+func (m *Model) mark(id string, on bool) {
+	if m.selection.marked == nil {
+		m.selection.marked = make(map[string]bool)
+	}
+	if on {
+		m.selection.marked[id] = true
+	} else {
+		delete(m.selection.marked, id)
+	}
+}
+
+func (m *Model) commitVisual() {
+	lo, hi, ok := m.visualRange()
+	if ok {
+		display := m.displayMarkets()
+		for i := lo; i <= hi; i++ {
+			m.mark(display[i].ID, true)
+		}
+	}
+	m.selection.visual = false
+	m.selection.anchor = ""
+}
+
+func (m *Model) clearSelection() bool {
+	if len(m.selection.marked) == 0 && !m.selection.visual {
+		return false
+	}
+	m.selection = selectionState{}
+	return true
+}
+
+func actionToggleMark(m *Model, _ string) tea.Cmd {
+	market := m.currentMarket()
+	if market == nil {
+		return nil
+	}
+	m.mark(market.ID, !m.selection.marked[market.ID])
+	return actionMoveDown(m, "")
+}
+
+func actionToggleVisual(m *Model, _ string) tea.Cmd {
+	if m.selection.visual {
+		m.commitVisual()
+		m.setStatus("%d markets selected", len(m.markedMarkets()))
+		return nil
+	}
+	market := m.currentMarket()
+	if market == nil {
+		return nil
+	}
+	m.selection.visual = true
+	m.selection.anchor = market.ID
+	m.setStatus("Visual select: move to extend, %s to finish", m.keys.binding("select.visual").Help().Key)
+	return nil
+}
+
+func actionExtendSelection(delta int) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		if market := m.currentMarket(); market != nil {
+			m.mark(market.ID, true)
+		}
+		if delta < 0 {
+			actionMoveUp(m, "")
+		} else {
+			actionMoveDown(m, "")
+		}
+		if market := m.currentMarket(); market != nil {
+			m.mark(market.ID, true)
+		}
+		return nil
+	}
+}
+
+func actionClearSelection(m *Model, _ string) tea.Cmd {
+	m.clearSelection()
+	return nil
+}
+
+func actionToggleWatch(m *Model, _ string) tea.Cmd {
+	targets := m.targetMarkets()
+	if len(targets) == 0 {
+		m.setError(fmt.Errorf("no market selected"))
+		return nil
+	}
+	watch := false
+	for i := range targets {
+		if !m.cfg.Watching(targets[i].ID) {
+			watch = true
+		}
+	}
+	for i := range targets {
+		m.cfg.SetWatching(targets[i].ID, watch)
+	}
+	if err := config.Save(m.cfg); err != nil {
+		m.setError(err)
+		return nil
+	}
+	if watch {
+		m.setStatus("Added %d markets to the watchlist", len(targets))
+	} else {
+		m.setStatus("Removed %d markets from the watchlist", len(targets))
+	}
+	m.clearSelection()
+	return nil
+}
+
+func actionCopy(field string) func(m *Model, _ string) tea.Cmd {
+	return func(m *Model, _ string) tea.Cmd {
+		targets := m.targetMarkets()
+		if len(targets) == 0 {
+			m.setError(fmt.Errorf("no market selected"))
+			return nil
+		}
+		values := make([]string, len(targets))
+		for i := range targets {
+			values[i] = targets[i].ID
+			if field == "slug" {
+				values[i] = targets[i].GetSlug()
+			}
+		}
+		seq, err := clipboardSequence(strings.Join(values, "\n"))
+		if err != nil {
+			m.setError(fmt.Errorf("copying to clipboard: %w", err))
+			return nil
+		}
+		now := time.Now()
+		m.clipboard, m.copiedAt = seq, now
+		m.setStatus("Copied %d market %ss to the clipboard", len(values), field)
+		return tea.Tick(clipboardHold, func(time.Time) tea.Msg {
+			return clipboardSentMsg(now)
+		})
+	}
+}
+
+// clipboardSequence builds the OSC 52 sequence that copies s. View prefixes
+// it to the frame until clipboardSentMsg arrives, so the renderer writes it
+// together with a frame instead of racing its output.
+func clipboardSequence(s string) (string, error) {
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return "", errors.New("output is not a terminal")
+	}
+	seq := osc52.New(s)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	return seq.String(), nil
+}
+
+func (m *Model) clipboardSent(at time.Time) {
+	if m.copiedAt.Equal(at) {
+		m.clipboard = ""
+	}
+}
+
+func getWatchlist(markets []types.Market, watchlist []string, limit int) []types.Market {
+	var filtered []types.Market
+	for _, id := range watchlist {
+		if i := marketIndex(markets, id); i >= 0 {
+			filtered = append(filtered, markets[i])
+		}
+	}
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"polyterm/api"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	splitGap           = 2
+	splitMinPanelWidth = 44
+)
+
+func (m Model) tableWidth() int {
+	widths, headers := m.tableHeaders()
+	return lipgloss.Width(renderHeaderRow(headers, widths))
+}
+
+func (m Model) splitFits() bool {
+	return m.width-m.tableWidth()-splitGap >= splitMinPanelWidth
+}
+
+func (m Model) splitLayout() bool {
+	return m.splitFits() && !m.splitHidden
+}
+
+func actionToggleSplit(m *Model, _ string) tea.Cmd {
+	if !m.splitFits() {
+		m.setError(fmt.Errorf("terminal too narrow for the split layout (need %d columns)", m.tableWidth()+splitGap+splitMinPanelWidth))
+		return nil
+	}
+	m.splitHidden = !m.splitHidden
+	return nil
+}
+
+func (m Model) renderSplitTable() string {
+	table := m.renderTable()
+	if !m.splitLayout() {
+		return table
+	}
+	width := m.width - m.tableWidth() - splitGap
+	panel := m.renderSidePanel(width, max(lipgloss.Height(table), m.maxDisplay+2))
+	return lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(m.tableWidth()).Render(table), strings.Repeat(" ", splitGap), panel)
+}
+
+func (m Model) renderSidePanel(width, height int) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorSecondary).
+		Padding(0, 1).
+		Width(width - 2).
+		Height(height - 2)
+	inner := width - 4
+
+	market := m.currentMarket()
+	if market == nil {
+		return box.Render(MutedStyle.Render("No market selected"))
+	}
+	yesOdds, noOdds := api.ParseOdds(market)
+
+	label := lipgloss.NewStyle().Foreground(colorMuted)
+	stat := func(name, value string, style lipgloss.Style) string {
+		return label.Render(fmt.Sprintf("%-11s", name)) + style.Render(value)
+	}
+	end := "N/A"
+	if t, err := time.Parse(time.RFC3339, market.EndDate); err == nil {
+		end = fmt.Sprintf("%s (%s)", t.Format("Jan 02, 2006"), formatRemaining(time.Until(t)))
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Foreground(colorSubtle).Bold(true).Render(wrapLines(market.Question, inner, 3)),
+		"",
+		renderProbabilityBar(yesOdds, noOdds, inner+20),
+		"",
+		stat("24h Vol", formatCurrency(market.Volume24hr), VolumeStyle.UnsetPadding()),
+		stat("Total Vol", formatCurrency(market.GetVolume()), StatsValueStyle),
+		stat("Liquidity", formatCurrency(market.GetLiquidity()), StatsValueStyle),
+		stat("Spread", fmt.Sprintf("%.1f¢", market.GetSpread()*100), StatsValueStyle),
+		stat("1h / 24h", "", lipgloss.NewStyle()) +
+			getPriceChangeStyle(market.OneHourPriceChange).Render(fmt.Sprintf("%+.2f%%", market.OneHourPriceChange*100)) +
+			label.Render(" / ") +
+			getPriceChangeStyle(market.OneDayPriceChange).Render(fmt.Sprintf("%+.2f%%", market.OneDayPriceChange*100)),
+		stat("Closes", end, StatsValueStyle),
+		stat("Category", getCategory(market.GetCategory()), StatsValueStyle),
+	}
+	if signals := m.signals[market.ID]; signals.Unusual() {
+		lines = append(lines, lipgloss.NewStyle().Foreground(colorWarning).Bold(true).Render(truncate("Unusual: "+strings.Join(signals.Reasons, ", "), inner)))
+	}
+
+	points, _ := m.priceHistory(market)
+	values := make([]float64, len(points))
+	lo, hi := 1.0, 0.0
+	for i, p := range points {
+		values[i] = p.yes
+		lo, hi = min(lo, p.yes), max(hi, p.yes)
+	}
+	if hi > lo {
+		for i := range values {
+			values[i] = (values[i] - lo) / (hi - lo)
+		}
+	}
+	lines = append(lines,
+		"",
+		label.Render(fmt.Sprintf("YES %.1f%% - %.1f%% since %s", lo*100, hi*100, formatTime(points[0].at))),
+		lipgloss.NewStyle().Foreground(colorAccent).Render(sparkline(values, inner)),
+	)
+
+	if market.Description != "" {
+		if rest := height - 2 - len(lines) - 2; rest > 0 {
+			lines = append(lines, "", lipgloss.NewStyle().Foreground(colorSubtle).Render(wrapLines(market.Description, inner, rest)))
+		}
+	}
+	content := strings.Split(strings.Join(lines, "\n"), "\n")
+	if len(content) > height-2 {
+		content = content[:height-2]
+	}
+	return box.Render(strings.Join(content, "\n"))
+}
@@ -3,90 +3,181 @@ package ui
 import "github.com/charmbracelet/lipgloss"
 
 var (
-	polyBlue   = lipgloss.Color("#6366F1")
-	polyPurple = lipgloss.Color("#8B5CF6")
-	polyPink   = lipgloss.Color("#EC4899")
-	polyDark   = lipgloss.Color("#1E1B4B")
-	polyLight  = lipgloss.Color("#C7D2FE")
-	
-	greenYes   = lipgloss.Color("#10B981")
-	redNo      = lipgloss.Color("#EF4444")
-	yellowWarn = lipgloss.Color("#F59E0B")
-	grayMuted  = lipgloss.Color("#6B7280")
-	
+	currentTheme Theme
+
+	colorPrimary       lipgloss.TerminalColor
+	colorSecondary     lipgloss.TerminalColor
+	colorAccent        lipgloss.TerminalColor
+	colorSurface       lipgloss.TerminalColor
+	colorSubtle        lipgloss.TerminalColor
+	colorText          lipgloss.TerminalColor
+	colorBright        lipgloss.TerminalColor
+	colorDim           lipgloss.TerminalColor
+	colorMuted         lipgloss.TerminalColor
+	colorPositive      lipgloss.TerminalColor
+	colorNegative      lipgloss.TerminalColor
+	colorWarning       lipgloss.TerminalColor
+	colorSelection     lipgloss.TerminalColor
+	colorSelectionText lipgloss.TerminalColor
+	colorStripe        lipgloss.TerminalColor
+	colorTabActive     lipgloss.TerminalColor
+	colorTabText       lipgloss.TerminalColor
+
+	TitleStyle       lipgloss.Style
+	BrandStyle       lipgloss.Style
+	HeaderStyle      lipgloss.Style
+	StatsBoxStyle    lipgloss.Style
+	StatsLabelStyle  lipgloss.Style
+	StatsValueStyle  lipgloss.Style
+	TableHeaderStyle lipgloss.Style
+	TableCellStyle   lipgloss.Style
+	SelectedRowStyle lipgloss.Style
+	StripedRowStyle  lipgloss.Style
+	MarkedRowStyle   lipgloss.Style
+	YesOddsStyle     lipgloss.Style
+	NoOddsStyle      lipgloss.Style
+	VolumeStyle      lipgloss.Style
+	FlashUpStyle     lipgloss.Style
+	FlashDownStyle   lipgloss.Style
+	ErrorStyle       lipgloss.Style
+	MutedStyle       lipgloss.Style
+	HelpStyle        lipgloss.Style
+	LoadingStyle     lipgloss.Style
+)
+
+func init() {
+	applyTheme(builtinThemes["dark"])
+}
+
+func applyTheme(t Theme) {
+	currentTheme = t
+
+	colorPrimary = t.Primary
+	colorSecondary = t.Secondary
+	colorAccent = t.Accent
+	colorSurface = t.Surface
+	colorSubtle = t.Subtle
+	colorText = t.Text
+	colorBright = t.Bright
+	colorDim = t.Dim
+	colorMuted = t.Muted
+	colorPositive = t.Positive
+	colorNegative = t.Negative
+	colorWarning = t.Warning
+	colorSelection = t.Selection
+	colorSelectionText = t.SelectionText
+	colorStripe = t.Stripe
+	colorTabActive = t.TabActive
+	colorTabText = t.TabText
+
 	TitleStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(polyBlue).
-		Background(polyDark).
+		Foreground(colorPrimary).
+		Background(colorSurface).
 		Padding(0, 2).
 		MarginBottom(1)
-	
+
 	BrandStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(polyPink).
-		Background(polyDark).
+		Foreground(colorAccent).
+		Background(colorSurface).
 		Padding(0, 2)
-	
+
 	HeaderStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(polyLight).
-		Background(polyDark).
+		Foreground(colorSubtle).
+		Background(colorSurface).
 		Padding(0, 1)
-	
+
 	StatsBoxStyle = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(polyBlue).
+		BorderForeground(colorPrimary).
 		Padding(0, 2).
 		MarginRight(2)
-	
+
 	StatsLabelStyle = lipgloss.NewStyle().
-		Foreground(grayMuted).
+		Foreground(colorMuted).
 		Bold(false)
-	
+
 	StatsValueStyle = lipgloss.NewStyle().
-		Foreground(polyBlue).
+		Foreground(colorPrimary).
 		Bold(true)
-	
+
 	TableHeaderStyle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(polyPurple).
+		Foreground(colorSecondary).
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(polyBlue).
+		BorderForeground(colorPrimary).
 		BorderBottom(true).
 		Padding(0, 1)
-	
+
 	TableCellStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#E5E7EB")).
+		Foreground(colorText).
 		Padding(0, 1)
-	
+
+	SelectedRowStyle = TableCellStyle.
+		Foreground(colorSelectionText).
+		Background(colorSelection).
+		Bold(true)
+
+	StripedRowStyle = TableCellStyle.
+		Background(colorStripe)
+
+	MarkedRowStyle = TableCellStyle.
+		Foreground(colorAccent).
+		Background(colorSurface).
+		Bold(true)
+
+	if colorless() {
+		SelectedRowStyle = SelectedRowStyle.Reverse(true)
+		MarkedRowStyle = MarkedRowStyle.Underline(true)
+	}
+
 	YesOddsStyle = lipgloss.NewStyle().
-		Foreground(greenYes).
+		Foreground(colorPositive).
 		Bold(true).
 		Padding(0, 1)
-	
+
 	NoOddsStyle = lipgloss.NewStyle().
-		Foreground(redNo).
+		Foreground(colorNegative).
 		Bold(true).
 		Padding(0, 1)
-	
+
 	VolumeStyle = lipgloss.NewStyle().
-		Foreground(polyPink).
+		Foreground(colorAccent).
 		Bold(true).
 		Padding(0, 1)
-	
+
+	FlashUpStyle = lipgloss.NewStyle().
+		Foreground(colorSurface).
+		Background(colorPositive).
+		Bold(true).
+		Padding(0, 1)
+
+	FlashDownStyle = lipgloss.NewStyle().
+		Foreground(colorSurface).
+		Background(colorNegative).
+		Bold(true).
+		Padding(0, 1)
+
+	if colorless() {
+		FlashUpStyle = FlashUpStyle.Reverse(true)
+		FlashDownStyle = FlashDownStyle.Reverse(true)
+	}
+
 	ErrorStyle = lipgloss.NewStyle().
-		Foreground(redNo).
+		Foreground(colorNegative).
 		Bold(true)
-	
+
 	MutedStyle = lipgloss.NewStyle().
-		Foreground(grayMuted)
-	
+		Foreground(colorMuted)
+
 	HelpStyle = lipgloss.NewStyle().
-		Foreground(grayMuted).
+		Foreground(colorMuted).
 		Italic(true).
 		MarginTop(1)
-	
+
 	LoadingStyle = lipgloss.NewStyle().
-		Foreground(polyPurple).
+		Foreground(colorSecondary).
 		Bold(true)
-)
+}
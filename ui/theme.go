@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"polyterm/config"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const autoTheme = "auto"
+
+type Theme struct {
+	Name          string
+	Primary       lipgloss.TerminalColor
+	Secondary     lipgloss.TerminalColor
+	Accent        lipgloss.TerminalColor
+	Surface       lipgloss.TerminalColor
+	Subtle        lipgloss.TerminalColor
+	Text          lipgloss.TerminalColor
+	Bright        lipgloss.TerminalColor
+	Dim           lipgloss.TerminalColor
+	Muted         lipgloss.TerminalColor
+	Positive      lipgloss.TerminalColor
+	Negative      lipgloss.TerminalColor
+	Warning       lipgloss.TerminalColor
+	Selection     lipgloss.TerminalColor
+	SelectionText lipgloss.TerminalColor
+	Stripe        lipgloss.TerminalColor
+	TabActive     lipgloss.TerminalColor
+	TabText       lipgloss.TerminalColor
+}
+
+func color(hex, ansi256, ansi string) lipgloss.TerminalColor {
+	return lipgloss.CompleteColor{TrueColor: hex, ANSI256: ansi256, ANSI: ansi}
+}
+
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Name:          "dark",
+		Primary:       color("#6366F1", "63", "12"),
+		Secondary:     color("#8B5CF6", "99", "5"),
+		Accent:        color("#EC4899", "205", "13"),
+		Surface:       color("#1E1B4B", "17", "0"),
+		Subtle:        color("#C7D2FE", "189", "7"),
+		Text:          color("#E5E7EB", "254", "7"),
+		Bright:        color("#FFFFFF", "231", "15"),
+		Dim:           color("#9CA3AF", "248", "7"),
+		Muted:         color("#6B7280", "243", "8"),
+		Positive:      color("#10B981", "36", "2"),
+		Negative:      color("#EF4444", "203", "1"),
+		Warning:       color("#F59E0B", "214", "3"),
+		Selection:     color("#6366F1", "63", "4"),
+		SelectionText: color("#E5E7EB", "254", "15"),
+		Stripe:        color("#1F2937", "235", "0"),
+		TabActive:     color("#4F46E5", "62", "4"),
+		TabText:       color("#FFFFFF", "231", "15"),
+	},
+	"light": {
+		Name:          "light",
+		Primary:       color("#4338CA", "61", "4"),
+		Secondary:     color("#6D28D9", "56", "5"),
+		Accent:        color("#BE185D", "161", "5"),
+		Surface:       color("#E0E7FF", "189", "7"),
+		Subtle:        color("#312E81", "54", "4"),
+		Text:          color("#1F2937", "235", "0"),
+		Bright:        color("#111827", "233", "0"),
+		Dim:           color("#4B5563", "240", "8"),
+		Muted:         color("#6B7280", "243", "8"),
+		Positive:      color("#047857", "29", "2"),
+		Negative:      color("#B91C1C", "124", "1"),
+		Warning:       color("#B45309", "130", "3"),
+		Selection:     color("#C7D2FE", "189", "6"),
+		SelectionText: color("#111827", "233", "0"),
+		Stripe:        color("#F3F4F6", "255", "7"),
+		TabActive:     color("#4338CA", "61", "4"),
+		TabText:       color("#FFFFFF", "231", "15"),
+	},
+	"high-contrast": {
+		Name:          "high-contrast",
+		Primary:       color("#00FFFF", "51", "14"),
+		Secondary:     color("#FFFF00", "226", "11"),
+		Accent:        color("#FF00FF", "201", "13"),
+		Surface:       color("#000000", "16", "0"),
+		Subtle:        color("#FFFFFF", "231", "15"),
+		Text:          color("#FFFFFF", "231", "15"),
+		Bright:        color("#FFFFFF", "231", "15"),
+		Dim:           color("#D0D0D0", "252", "7"),
+		Muted:         color("#BCBCBC", "250", "7"),
+		Positive:      color("#00FF00", "46", "10"),
+		Negative:      color("#FF3030", "196", "9"),
+		Warning:       color("#FFD700", "220", "11"),
+		Selection:     color("#FFFF00", "226", "11"),
+		SelectionText: color("#000000", "16", "0"),
+		Stripe:        color("#262626", "235", "0"),
+		TabActive:     color("#0000FF", "21", "12"),
+		TabText:       color("#FFFFFF", "231", "15"),
+	},
+	"colorblind": {
+		Name:          "colorblind",
+		Primary:       color("#56B4E9", "74", "14"),
+		Secondary:     color("#CC79A7", "175", "5"),
+		Accent:        color("#F0E442", "221", "11"),
+		Surface:       color("#1C1C1C", "234", "0"),
+		Subtle:        color("#D0D0D0", "252", "7"),
+		Text:          color("#E4E4E4", "254", "7"),
+		Bright:        color("#FFFFFF", "231", "15"),
+		Dim:           color("#A8A8A8", "248", "7"),
+		Muted:         color("#808080", "244", "8"),
+		Positive:      color("#0072B2", "25", "4"),
+		Negative:      color("#E69F00", "214", "3"),
+		Warning:       color("#F0E442", "221", "11"),
+		Selection:     color("#005F87", "24", "4"),
+		SelectionText: color("#FFFFFF", "231", "15"),
+		Stripe:        color("#262626", "235", "0"),
+		TabActive:     color("#0072B2", "25", "4"),
+		TabText:       color("#FFFFFF", "231", "15"),
+	},
+}
+
+var themeRoles = map[string]func(t *Theme) *lipgloss.TerminalColor{
+	"primary":       func(t *Theme) *lipgloss.TerminalColor { return &t.Primary },
+	"secondary":     func(t *Theme) *lipgloss.TerminalColor { return &t.Secondary },
+	"accent":        func(t *Theme) *lipgloss.TerminalColor { return &t.Accent },
+	"surface":       func(t *Theme) *lipgloss.TerminalColor { return &t.Surface },
+	"subtle":        func(t *Theme) *lipgloss.TerminalColor { return &t.Subtle },
+	"text":          func(t *Theme) *lipgloss.TerminalColor { return &t.Text },
+	"bright":        func(t *Theme) *lipgloss.TerminalColor { return &t.Bright },
+	"dim":           func(t *Theme) *lipgloss.TerminalColor { return &t.Dim },
+	"muted":         func(t *Theme) *lipgloss.TerminalColor { return &t.Muted },
+	"positive":      func(t *Theme) *lipgloss.TerminalColor { return &t.Positive },
+	"negative":      func(t *Theme) *lipgloss.TerminalColor { return &t.Negative },
+	"warning":       func(t *Theme) *lipgloss.TerminalColor { return &t.Warning },
+	"selection":     func(t *Theme) *lipgloss.TerminalColor { return &t.Selection },
+	"selectionText": func(t *Theme) *lipgloss.TerminalColor { return &t.SelectionText },
+	"stripe":        func(t *Theme) *lipgloss.TerminalColor { return &t.Stripe },
+	"tabActive":     func(t *Theme) *lipgloss.TerminalColor { return &t.TabActive },
+	"tabText":       func(t *Theme) *lipgloss.TerminalColor { return &t.TabText },
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func resolveTheme(name string, custom map[string]config.Palette) (Theme, error) {
+	if name == "" || name == autoTheme {
+		if lipgloss.HasDarkBackground() {
+			return builtinThemes["dark"], nil
+		}
+		return builtinThemes["light"], nil
+	}
+
+	if t, ok := builtinThemes[name]; ok {
+		return t, nil
+	}
+
+	palette, ok := custom[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(themeNames(custom), ", "))
+	}
+
+	base := palette.Base
+	if base == "" {
+		base = autoTheme
+	}
+	if _, isCustom := custom[base]; isCustom || base == name {
+		return Theme{}, fmt.Errorf("theme %q: base must be a built-in theme, got %q", name, base)
+	}
+	t, err := resolveTheme(base, nil)
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %q: %w", name, err)
+	}
+	t.Name = name
+
+	for role, value := range palette.Colors {
+		field, ok := themeRoles[role]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme %q: unknown color role %q", name, role)
+		}
+		c, err := parseColor(value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme %q: %s: %w", name, role, err)
+		}
+		*field(&t) = c
+	}
+	return t, nil
+}
+
+func parseColor(s string) (lipgloss.TerminalColor, error) {
+	if hexColor.MatchString(s) {
+		return lipgloss.Color(s), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(s), nil
+	}
+	return nil, fmt.Errorf("invalid color %q (use #RRGGBB or an ANSI number 0-255)", s)
+}
+
+func themeNames(custom map[string]config.Palette) []string {
+	names := []string{autoTheme}
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+func colorless() bool {
+	return lipgloss.ColorProfile() == termenv.Ascii
+}
@@ -1,10 +1,14 @@
 package ui
 
 import (
+	"fmt"
 	"time"
 
+	"polyterm/metrics"
+	"polyterm/search"
 	"polyterm/types"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -19,247 +23,116 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.maxDisplay > 25 {
 			m.maxDisplay = 25
 		}
+		m.syncDescription()
 		if !m.ready {
 			m.ready = true
 		}
 		return m, nil
 
 	case types.FetchResult:
-		m.loading = false
-		m.err = msg.Err
+		m.record(msg)
+		return m, m.applyFetchResult(msg, time.Now())
+
+	case types.ClosedResult:
+		m.resolvedLoading = false
+		m.resolvedErr = msg.Err
 		if msg.Err == nil {
-			m.markets = msg.Markets
-			m.stats = msg.Stats
-			m.lastUpdate = time.Now()
-			m.applyFiltersAndSort()
-		}
-		if !m.ready {
-			m.ready = true
+			m.setResolved(msg.Markets)
 		}
 		return m, nil
 
+	case types.ArbitrageResult:
+		m.record(msg)
+		m.applyArbitrageResult(msg)
+		return m, nil
+
+	case replayTickMsg:
+		return m, m.advanceReplay()
+
+	case marketRefResult:
+		m.applyMarketRefResult(msg)
+		return m, nil
+
+	case browserResult:
+		m.applyBrowserResult(msg)
+		return m, nil
+
+	case resolveTickMsg:
+		return m, resolveCmd(m.store)
+
+	case resolveResult:
+		return m, m.applyResolveResult(msg)
+
+	case flashExpiredMsg:
+		m.expireFlashes(time.Time(msg))
+		return m, nil
+
+	case clipboardSentMsg:
+		m.clipboardSent(time.Time(msg))
+		return m, nil
+
 	case tickMsg:
 		if m.autoRefresh && !m.loading {
 			return m, tea.Batch(
-				fetchMarketsCmd(500),
+				fetchMarketsCmd(m.cache, 500),
 				tickCmd(),
 			)
 		}
 		return m, tickCmd()
 
+	case tea.MouseMsg:
+		if m.palette.open || m.showHelp || m.searchMode {
+			return m, nil
+		}
+		return m.updateMouse(msg)
+
 	case tea.KeyMsg:
+		if m.palette.open {
+			return m.updatePalette(msg)
+		}
+
+		if m.showHelp {
+			switch {
+			case msg.String() == "ctrl+c":
+				return m, tea.Quit
+			case key.Matches(msg, m.keys.binding("help"), m.keys.binding("back"), m.keys.binding("quit")):
+				m.showHelp = false
+			}
+			return m, nil
+		}
+
 		if m.searchMode {
+			query := m.searchTarget()
 			switch msg.String() {
 			case "esc", "enter":
 				m.searchMode = false
-				m.applyFiltersAndSort()
+				m.applySearch()
 				return m, nil
 			case "backspace":
-				if len(m.searchQuery) > 0 {
-					m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
-					m.applyFiltersAndSort()
+				if len(*query) > 0 {
+					*query = (*query)[:len(*query)-1]
+					m.applySearch()
 				}
 				return m, nil
 			case "ctrl+u":
-				m.searchQuery = ""
-				m.applyFiltersAndSort()
+				*query = ""
+				m.applySearch()
 				return m, nil
 			default:
 				if len(msg.String()) == 1 {
-					m.searchQuery += msg.String()
-					m.applyFiltersAndSort()
+					*query += msg.String()
+					m.applySearch()
 				}
 				return m, nil
 			}
 		}
 		
-		switch msg.String() {
-		case "q", "ctrl+c":
-			return m, tea.Quit
-		
-		case "esc":
-			if m.currentView == viewDetail {
-				m.currentView = viewList
-				m.selectedMarket = -1
-				return m, nil
-			}
-			return m, tea.Quit
-		
-		case "r":
-			if m.currentView == viewList {
-				m.loading = true
-				m.err = nil
-				return m, tea.Batch(m.spinner.Tick, fetchMarketsCmd(500))
-			}
-			return m, nil
-		
-		case "a":
-			m.autoRefresh = !m.autoRefresh
-			return m, nil
-		
-		case "/":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				m.searchMode = true
-				return m, nil
-			}
-			return m, nil
-		
-		case "f":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				m.filterBy = (m.filterBy + 1) % 5
-				m.cursor = 0
-				m.scroll = 0
-				m.applyFiltersAndSort()
-				return m, nil
-			}
-			return m, nil
-		
-		case "s":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				m.sortBy = (m.sortBy + 1) % 3
-				m.cursor = 0
-				m.scroll = 0
-				m.applyFiltersAndSort()
-				return m, nil
-			}
-			return m, nil
-		
-		case "c":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				m.searchQuery = ""
-				m.filterBy = filterAll
-				m.sortBy = sortVolume
-				m.cursor = 0
-				m.scroll = 0
-				m.applyFiltersAndSort()
-				return m, nil
-			}
-			return m, nil
-		
-		case "tab":
-			if m.currentView == viewList {
-				m.currentPage = (m.currentPage + 1) % 2
-				return m, nil
-			}
-			return m, nil
-		
-		case "1":
-			if m.currentView == viewList {
-				m.currentPage = pageMarkets
-				return m, nil
-			}
-			return m, nil
-		
-		case "2":
-			if m.currentView == viewList {
-				m.currentPage = pageStats
-				return m, nil
-			}
-			return m, nil
-		
-		case "enter":
-			if m.currentView == viewList && m.currentPage == pageMarkets && len(m.filteredMarkets) > 0 {
-				m.selectedMarket = m.cursor
-				if m.selectedMarket < len(m.filteredMarkets) {
-					m.currentView = viewDetail
-				}
-				return m, nil
-			}
-			return m, nil
-		
-		case "up", "k":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				if m.cursor > 0 {
-					m.cursor--
-					
-					middle := m.maxDisplay / 2
-					if m.cursor < m.scroll+middle && m.scroll > 0 {
-						m.scroll--
-					}
-				}
-			}
-			return m, nil
-		
-		case "down", "j":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				maxLen := len(m.filteredMarkets)
-				if maxLen == 0 {
-					maxLen = len(m.markets)
-				}
-				if m.cursor < maxLen-1 {
-					m.cursor++
-					
-					middle := m.maxDisplay / 2
-					maxScroll := maxLen - m.maxDisplay
-					if maxScroll < 0 {
-						maxScroll = 0
-					}
-					
-					if m.cursor >= m.scroll+middle && m.scroll < maxScroll {
-						m.scroll++
-					}
-				}
-			}
-			return m, nil
-		
-		case "home", "g":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				m.cursor = 0
-				m.scroll = 0
-			}
-			return m, nil
-		
-		case "end", "G":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				maxLen := len(m.filteredMarkets)
-				if maxLen == 0 {
-					maxLen = len(m.markets)
-				}
-				m.cursor = maxLen - 1
-				maxScroll := maxLen - m.maxDisplay
-				if maxScroll < 0 {
-					maxScroll = 0
-				}
-				m.scroll = maxScroll
-			}
-			return m, nil
-		
-		case "pageup":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				m.cursor -= m.maxDisplay
-				if m.cursor < 0 {
-					m.cursor = 0
-				}
-				m.scroll -= m.maxDisplay
-				if m.scroll < 0 {
-					m.scroll = 0
-				}
-			}
-			return m, nil
-		
-		case "pagedown":
-			if m.currentView == viewList && m.currentPage == pageMarkets {
-				maxLen := len(m.filteredMarkets)
-				if maxLen == 0 {
-					maxLen = len(m.markets)
-				}
-				maxCursor := maxLen - 1
-				m.cursor += m.maxDisplay
-				if m.cursor > maxCursor {
-					m.cursor = maxCursor
-				}
-				
-				maxScroll := maxLen - m.maxDisplay
-				if maxScroll < 0 {
-					maxScroll = 0
-				}
-				m.scroll += m.maxDisplay
-				if m.scroll > maxScroll {
-					m.scroll = maxScroll
-				}
-			}
-			return m, nil
+		m.status = ""
+		if a := m.actionForKey(msg); a != nil {
+			cmd := a.run(&m, "")
+			return m, cmd
 		}
+		return m, nil
 	}
 
 	if m.loading {
@@ -270,3 +143,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	return m, nil
 }
+
+// applyFetchResult installs a market listing fetched at at, which is the
+// recorded frame time during replay.
+func (m *Model) applyFetchResult(msg types.FetchResult, at time.Time) tea.Cmd {
+	m.loading = false
+	m.err = msg.Err
+	var cmds []tea.Cmd
+	if msg.Err == nil {
+		cmds = append(cmds, m.trackChanges(m.markets, msg.Markets))
+		m.markets = msg.Markets
+		m.index = search.NewIndex(m.markets)
+		m.stats = msg.Stats
+		m.lastUpdate = at
+		m.applyFiltersAndSort()
+		m.signals = metrics.ComputeAll(m.markets, m.store, m.lastUpdate)
+		if m.store != nil {
+			if _, err := m.store.Record(m.lastUpdate, m.markets); err != nil {
+				m.setError(fmt.Errorf("recording snapshot: %w", err))
+			}
+		}
+		if !m.replaying() {
+			for _, event := range m.alerts.Evaluate(m.markets) {
+				m.setStatus("ALERT: %s (now %.2f)", event.Rule, event.Value)
+			}
+		}
+		cmds = append(cmds, m.openPending())
+	}
+	if !m.ready {
+		m.ready = true
+	}
+	if msg.Err == nil && !m.arbScanning && !m.replaying() {
+		m.arbScanning = true
+		cmds = append(cmds, scanArbitrageCmd(m.markets, m.cfg.Arbitrage.FeeBps))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) applyArbitrageResult(msg types.ArbitrageResult) {
+	m.arbScanning = false
+	m.arbErr = msg.Err
+	if msg.Groups != nil || msg.Err == nil {
+		m.arbGroups = msg.Groups
+	}
+	m.moveArbCursor(0)
+}
@@ -2,17 +2,22 @@ package ui
 
 import (
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
 	"polyterm/api"
-	"polyterm/types"
+	"polyterm/config"
+	"polyterm/metrics"
+	"polyterm/search"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
 func (m Model) View() string {
+	return m.clipboard + m.view()
+}
+
+func (m Model) view() string {
 	if !m.ready {
 		return "\n  Initializing...\n"
 	}
@@ -41,56 +46,123 @@ func (m Model) View() string {
 		)
 	}
 
-	if m.currentView == viewDetail && m.selectedMarket >= 0 && m.selectedMarket < len(m.markets) {
+	if m.palette.open {
+		return m.renderPalette()
+	}
+
+	if m.showHelp {
+		return m.renderKeyHelp()
+	}
+
+	if m.currentView == viewDetail && m.selectedID != "" {
 		return m.renderMarketDetail()
 	}
 
+	if m.currentView == viewCompare && len(m.compare) > 0 {
+		return m.renderCompare()
+	}
+
 	switch m.currentPage {
 	case pageMarkets:
 		return m.renderMarketsPage()
 	case pageStats:
 		return m.renderStatsPage()
+	case pageCategories:
+		return m.renderCategoriesPage()
+	case pageArbitrage:
+		return m.renderArbitragePage()
+	case pageResolved:
+		return m.renderResolvedPage()
 	default:
 		return m.renderMarketsPage()
 	}
 }
 
 func (m Model) renderMarketsPage() string {
-	header := m.renderHeader()
-	tabs := m.renderTabs()
-	filterBar := m.renderFilterBar()
-	stats := m.renderStats()
-	table := m.renderTable()
-	help := m.renderHelp()
+	blocks, _, _ := m.marketsPageBlocks()
+	return lipgloss.JoinVertical(lipgloss.Left, blocks...)
+}
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
+func (m Model) marketsPageBlocks() (blocks []string, tabsIdx, tableIdx int) {
+	blocks = []string{
 		"",
-		header,
-		tabs,
-		filterBar,
-		stats,
+		m.renderHeader(),
+		m.renderTabs(),
+		m.renderFilterBar(),
+		m.renderStats(),
 		"",
-		table,
-		help,
-	)
+		m.renderSplitTable(),
+		m.renderHelp(),
+	}
+	return blocks, 2, 6
 }
 
 func (m Model) renderStatsPage() string {
-	header := m.renderHeader()
-	tabs := m.renderTabs()
-	statsDetail := m.renderAdvancedStats()
-	help := m.renderHelp()
+	blocks, _ := m.statsPageBlocks()
+	return lipgloss.JoinVertical(lipgloss.Left, blocks...)
+}
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
+func (m Model) statsPageBlocks() (blocks []string, tabsIdx int) {
+	blocks = []string{
 		"",
-		header,
-		tabs,
+		m.renderHeader(),
+		m.renderTabs(),
 		"",
-		statsDetail,
-		help,
-	)
+		m.renderAnalytics(),
+		m.renderHelp(),
+	}
+	return blocks, 2
+}
+
+func (m Model) renderCategoriesPage() string {
+	blocks, _ := m.categoriesPageBlocks()
+	return lipgloss.JoinVertical(lipgloss.Left, blocks...)
+}
+
+func (m Model) categoriesPageBlocks() (blocks []string, tabsIdx int) {
+	blocks = []string{
+		"",
+		m.renderHeader(),
+		m.renderTabs(),
+		"",
+		m.renderCategories(),
+		m.renderHelp(),
+	}
+	return blocks, 2
+}
+
+func (m Model) renderArbitragePage() string {
+	blocks, _ := m.arbitragePageBlocks()
+	return lipgloss.JoinVertical(lipgloss.Left, blocks...)
+}
+
+func (m Model) arbitragePageBlocks() (blocks []string, tabsIdx int) {
+	blocks = []string{
+		"",
+		m.renderHeader(),
+		m.renderTabs(),
+		"",
+		m.renderArbitrage(),
+		m.renderHelp(),
+	}
+	return blocks, 2
+}
+
+func (m Model) renderResolvedPage() string {
+	blocks, _ := m.resolvedPageBlocks()
+	return lipgloss.JoinVertical(lipgloss.Left, blocks...)
+}
+
+func (m Model) resolvedPageBlocks() (blocks []string, tabsIdx int) {
+	blocks = []string{
+		"",
+		m.renderHeader(),
+		m.renderTabs(),
+		"",
+		m.renderResolved(),
+		m.renderHelp(),
+	}
+	return blocks, 2
 }
 
 func (m Model) renderHeader() string {
@@ -99,7 +171,7 @@ func (m Model) renderHeader() string {
 
 	timeStr := ""
 	if !m.lastUpdate.IsZero() {
-		timeStr = MutedStyle.Render(fmt.Sprintf("Updated: %s", m.lastUpdate.Format("15:04:05")))
+		timeStr = MutedStyle.Render(fmt.Sprintf("Updated: %s", m.lastUpdate.Local().Format("15:04:05")))
 	}
 
 	refreshStatus := ""
@@ -108,6 +180,9 @@ func (m Model) renderHeader() string {
 	} else {
 		refreshStatus = MutedStyle.Render("Auto: OFF")
 	}
+	if m.replaying() {
+		timeStr, refreshStatus = m.replayStatus()
+	}
 
 	headerLine := lipgloss.JoinHorizontal(
 		lipgloss.Top,
@@ -119,63 +194,65 @@ func (m Model) renderHeader() string {
 		"  ",
 		refreshStatus,
 	)
+	if counts := m.renderChangeCounts(); counts != "" {
+		headerLine += "  " + counts
+	}
 
 	return headerLine
 }
 
+var pageTabs = []struct {
+	page  pageMode
+	label string
+}{
+	{pageMarkets, "Markets"},
+	{pageStats, "Analytics"},
+	{pageCategories, "Categories"},
+	{pageArbitrage, "Arbitrage"},
+	{pageResolved, "Resolved"},
+}
+
 func (m Model) renderTabs() string {
+	return joinCells(m.tabLabels())
+}
+
+func (m Model) tabLabels() []string {
 	activeTab := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#4F46E5")).
+		Foreground(colorTabText).
+		Background(colorTabActive).
 		Padding(0, 2)
 
 	inactiveTab := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#9CA3AF")).
-		Background(lipgloss.Color("#1F2937")).
+		Foreground(colorDim).
+		Background(colorStripe).
 		Padding(0, 2)
 
-	tab1 := inactiveTab.Render("[1] Markets")
-	tab2 := inactiveTab.Render("[2] Analytics")
-
-	switch m.currentPage {
-	case pageMarkets:
-		tab1 = activeTab.Render("[1] Markets")
-	case pageStats:
-		tab2 = activeTab.Render("[2] Analytics")
+	tabs := make([]string, len(pageTabs))
+	for i, tab := range pageTabs {
+		style := inactiveTab
+		if tab.page == m.currentPage {
+			style = activeTab
+		}
+		tabs[i] = style.Render(fmt.Sprintf("[%d] %s", i+1, tab.label))
 	}
-
-	return lipgloss.JoinHorizontal(lipgloss.Top, tab1, " ", tab2)
+	return tabs
 }
 
 func (m Model) renderFilterBar() string {
-	sortName := ""
-	switch m.sortBy {
-	case sortVolume:
-		sortName = "Volume"
-	case sortChange:
-		sortName = "Change"
-	case sortLiquidity:
-		sortName = "Liquidity"
-	}
-
-	filterName := ""
-	switch m.filterBy {
-	case filterAll:
-		filterName = "All"
-	case filterCrypto:
-		filterName = "Crypto"
-	case filterPolitics:
-		filterName = "Politics"
-	case filterSports:
-		filterName = "Sports"
-	case filterEntertainment:
-		filterName = "Entertainment"
-	}
-
-	sortStyle := lipgloss.NewStyle().Foreground(polyPurple).Bold(true)
-	filterStyle := lipgloss.NewStyle().Foreground(polyBlue).Bold(true)
-	searchStyle := lipgloss.NewStyle().Foreground(polyPink).Bold(true)
+	sortName := api.SortNames[m.sortBy]
+	if m.searchQuery != "" {
+		sortName = "Relevance"
+	}
+
+	filterName := api.FilterNames[m.filterBy]
+	if m.categoryFilter != "" {
+		filterName += " · " + m.categoryFilter
+	}
+
+	sortStyle := lipgloss.NewStyle().Foreground(colorSecondary).Bold(true)
+	filterStyle := lipgloss.NewStyle().Foreground(colorPrimary).Bold(true)
+	searchStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true)
 
 	displayLen := len(m.filteredMarkets)
 	if displayLen == 0 && len(m.markets) > 0 {
@@ -196,16 +273,23 @@ func (m Model) renderFilterBar() string {
 
 	parts = append(parts, MutedStyle.Render(fmt.Sprintf("Results: %d", displayLen)))
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, parts[0], "  ", parts[1], "  ", parts[2], "  ", parts[3])
+	if m.selection.visual {
+		parts = append(parts, lipgloss.NewStyle().Foreground(colorWarning).Bold(true).Render("VISUAL"))
+	}
+	if n := len(m.markedMarkets()); n > 0 {
+		parts = append(parts, searchStyle.Render(fmt.Sprintf("%d selected", n)))
+	}
+
+	return strings.Join(parts, "  ")
 }
 
 func (m Model) renderStats() string {
 	compactStyle := lipgloss.NewStyle().
-		Foreground(grayMuted).
+		Foreground(colorMuted).
 		Bold(false)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(polyBlue).
+		Foreground(colorPrimary).
 		Bold(true)
 
 	parts := []string{
@@ -216,7 +300,7 @@ func (m Model) renderStats() string {
 	}
 
 	if m.stats.TopVolume != nil {
-		parts = append(parts, compactStyle.Render("Hot: ")+lipgloss.NewStyle().Foreground(polyPink).Render(truncate(m.stats.TopVolume.Question, 40)))
+		parts = append(parts, compactStyle.Render("Hot: ")+lipgloss.NewStyle().Foreground(colorAccent).Render(truncate(m.stats.TopVolume.Question, 40)))
 	}
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, parts[0], "  ", parts[1], "  ", parts[2], "  ", parts[3], "  ", parts[4])
@@ -231,20 +315,30 @@ func (m Model) renderStatBox(label, value string) string {
 	return StatsBoxStyle.Render(content)
 }
 
-func (m Model) renderTable() string {
-	displayMarkets := m.filteredMarkets
-	if len(displayMarkets) == 0 {
-		displayMarkets = m.markets
-	}
+type tableColumn struct {
+	title    string
+	width    int
+	sortable bool
+	sort     api.SortMode
+}
 
+var marketColumns = []tableColumn{
+	{title: "#", width: 6},
+	{title: "Market", width: 55},
+	{title: "Yes %", width: 12, sortable: true, sort: api.SortOdds},
+	{title: "No %", width: 12},
+	{title: "Total Vol", width: 15, sortable: true, sort: api.SortVolume},
+	{title: "24h Vol", width: 10, sortable: true, sort: api.SortVolume24h},
+}
+
+func (m Model) renderTable() string {
+	displayMarkets := m.displayMarkets()
 	if len(displayMarkets) == 0 {
 		return MutedStyle.Render("No markets available")
 	}
 
-	colWidths := []int{4, 55, 12, 12, 15, 10}
-
-	headers := []string{"#", "Market", "Yes %", "No %", "Total Vol", "24h Vol"}
-	headerRow := m.renderTableRow(headers, colWidths, TableHeaderStyle)
+	colWidths, headers := m.tableHeaders()
+	headerRow := renderHeaderRow(headers, colWidths)
 
 	var rows []string
 	rows = append(rows, headerRow)
@@ -260,8 +354,20 @@ func (m Model) renderTable() string {
 
 		yesOdds, noOdds := api.ParseOdds(&market)
 
+		marked := m.isMarked(i, market.ID)
+		rank := fmt.Sprintf("%d", i+1)
+		if marked {
+			rank = "+" + rank
+		}
+		if m.signals[market.ID].Unusual() {
+			rank += "!"
+		}
+		if m.cfg.Watching(market.ID) {
+			rank += "*"
+		}
+
 		cells := []string{
-			fmt.Sprintf("%d", i+1),
+			rank,
 			truncate(market.Question, 53),
 			fmt.Sprintf("%.1f%%", yesOdds),
 			fmt.Sprintf("%.1f%%", noOdds),
@@ -271,12 +377,19 @@ func (m Model) renderTable() string {
 
 		rowStyle := TableCellStyle
 		if i == m.cursor {
-			rowStyle = rowStyle.Background(lipgloss.Color("#6366F1")).Bold(true)
+			rowStyle = SelectedRowStyle
+		} else if marked {
+			rowStyle = MarkedRowStyle
 		} else if i%2 == 0 {
-			rowStyle = rowStyle.Background(lipgloss.Color("#1F2937"))
+			rowStyle = StripedRowStyle
+		}
+
+		var marks []bool
+		if m.searchQuery != "" {
+			marks = search.Highlight(cells[1], m.searchQuery)
 		}
 
-		row := m.renderTableRow(cells, colWidths, rowStyle)
+		row := m.renderTableRow(cells, colWidths, rowStyle, marks, m.flashStyles(market.ID))
 		rows = append(rows, row)
 	}
 
@@ -292,10 +405,39 @@ func (m Model) renderTable() string {
 	return strings.Join(rows, "\n")
 }
 
-func (m Model) renderTableRow(cells []string, widths []int, style lipgloss.Style) string {
+func (m Model) tableHeaders() ([]int, []string) {
+	widths := make([]int, len(marketColumns))
+	headers := make([]string, len(marketColumns))
+	for i, col := range marketColumns {
+		widths[i] = col.width
+		headers[i] = col.title
+		if col.sortable && col.sort == m.sortBy && m.searchQuery == "" {
+			headers[i] += " ▼"
+		}
+	}
+	return widths, headers
+}
+
+func renderHeaderRow(headers []string, widths []int) string {
+	cells := make([]string, len(headers))
+	for i, header := range headers {
+		cells[i] = renderHeaderCell(header, widths[i])
+	}
+	return joinCells(cells)
+}
+
+func renderHeaderCell(header string, width int) string {
+	return TableHeaderStyle.Render(fmt.Sprintf("%-*s", width, header))
+}
+
+func (m Model) renderTableRow(cells []string, widths []int, style lipgloss.Style, marks []bool, flashes map[int]lipgloss.Style) string {
 	var formatted []string
 	for i, cell := range cells {
 		width := widths[i]
+		if i == 1 && marks != nil {
+			formatted = append(formatted, renderHighlighted(cell, width, style, marks))
+			continue
+		}
 		if len(cell) > width {
 			cell = cell[:width]
 		}
@@ -308,335 +450,266 @@ func (m Model) renderTableRow(cells []string, widths []int, style lipgloss.Style
 		} else if i == 4 {
 			cellStyle = VolumeStyle
 		}
+		if flash, ok := flashes[i]; ok {
+			cellStyle = flash
+		}
 
 		padded := fmt.Sprintf("%-*s", width, cell)
 		formatted = append(formatted, cellStyle.Render(padded))
 	}
-	return strings.Join(formatted, " ")
+	return joinCells(formatted)
 }
 
-func (m Model) renderHelp() string {
-	var helps []string
-	if m.currentView == viewList {
-		if m.currentPage == pageMarkets {
-			if m.searchMode {
-				helps = []string{
-					"type to search",
-					"enter/esc: exit search",
-					"backspace: delete",
-					"ctrl+u: clear",
-				}
-			} else {
-				helps = []string{
-					"↑/↓ j/k: nav",
-					"enter: details",
-					"/: search",
-					"f: filter",
-					"s: sort",
-					"c: clear",
-					"q: quit",
-				}
-			}
-		} else {
-			helps = []string{
-				"1/2 or tab: switch page",
-				"r: refresh",
-				"a: auto-refresh",
-				"q: quit",
-			}
-		}
-	} else {
-		helps = []string{
-			"esc: back",
-			"q: quit",
+func joinCells(cells []string) string {
+	joined := make([]string, 0, len(cells)*2)
+	for i, cell := range cells {
+		if i > 0 {
+			joined = append(joined, " ")
 		}
+		joined = append(joined, cell)
 	}
-	return HelpStyle.Render(strings.Join(helps, " | "))
+	return lipgloss.JoinHorizontal(lipgloss.Top, joined...)
 }
 
-func (m Model) renderAdvancedStats() string {
-	if len(m.markets) == 0 {
-		return MutedStyle.Render("No data available")
-	}
-
-	var sections []string
-
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#1F2937")).
-		Padding(0, 2)
-
-	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
-
-	sections = append(sections, titleStyle.Render("PLATFORM OVERVIEW"))
-
-	platformStats := lipgloss.JoinVertical(
-		lipgloss.Left,
-		"",
-		labelStyle.Render("Total Markets:     ")+valueStyle.Render(fmt.Sprintf("%d", m.stats.TotalMarkets)),
-		labelStyle.Render("Active Markets:    ")+valueStyle.Render(fmt.Sprintf("%d", m.stats.ActiveMarkets)),
-		labelStyle.Render("24h Volume:        ")+valueStyle.Render(formatCurrency(m.stats.Volume24h)),
-		labelStyle.Render("Total Volume:      ")+valueStyle.Render(formatCurrency(m.stats.TotalVolume)),
-		labelStyle.Render("Avg Liquidity:     ")+valueStyle.Render(formatCurrency(m.stats.AvgLiquidity)),
-	)
-	sections = append(sections, platformStats)
-
-	topMarkets := getTopMarketsByVolume(m.markets, 10)
-	if len(topMarkets) > 0 {
-		sections = append(sections, "", "", titleStyle.Render("TOP 10 MARKETS BY TOTAL VOLUME"))
-		sections = append(sections, "")
-
-		for i, market := range topMarkets {
-			yesOdds, _ := api.ParseOdds(&market)
-			line := fmt.Sprintf("%2d. %-50s %8s  YES: %5.1f%%",
-				i+1,
-				truncate(market.Question, 48),
-				formatCurrency(market.GetVolume()),
-				yesOdds)
-			sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("#D1D5DB")).Render(line))
-		}
+func renderHighlighted(cell string, width int, style lipgloss.Style, marks []bool) string {
+	runes := []rune(cell)
+	if len(runes) > width {
+		runes = runes[:width]
 	}
-
-	topVolume24h := getTopMarketsByVolume24h(m.markets, 10)
-	if len(topVolume24h) > 0 {
-		sections = append(sections, "", "", titleStyle.Render("TOP 10 MARKETS BY 24H VOLUME"))
-		sections = append(sections, "")
-
-		for i, market := range topVolume24h {
-			yesOdds, _ := api.ParseOdds(&market)
-			line := fmt.Sprintf("%2d. %-50s %8s  YES: %5.1f%%",
-				i+1,
-				truncate(market.Question, 48),
-				formatCurrency(market.Volume24hr),
-				yesOdds)
-			sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("#D1D5DB")).Render(line))
-		}
+	for len(runes) < width {
+		runes = append(runes, ' ')
 	}
 
-	movers := getTopMovers(m.markets, 10)
-	if len(movers) > 0 {
-		sections = append(sections, "", "", titleStyle.Render("BIGGEST 24H PRICE MOVERS"))
-		sections = append(sections, "")
+	base := style.UnsetPadding()
+	match := base.Foreground(colorWarning).Underline(true)
 
-		for i, market := range movers {
-			changeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981")).Bold(true)
-			if market.OneDayPriceChange < 0 {
-				changeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")).Bold(true)
-			}
-
-			line := fmt.Sprintf("%2d. %-50s %s",
-				i+1,
-				truncate(market.Question, 48),
-				changeStyle.Render(fmt.Sprintf("%+6.2f%%", market.OneDayPriceChange*100)))
-			sections = append(sections, line)
+	var b strings.Builder
+	b.WriteString(base.Render(" "))
+	for start := 0; start < len(runes); {
+		marked := start < len(marks) && marks[start]
+		end := start + 1
+		for end < len(runes) && (end < len(marks) && marks[end]) == marked {
+			end++
 		}
-	}
-
-	momentum := getTopMomentum(m.markets, 10)
-	if len(momentum) > 0 {
-		sections = append(sections, "", "", titleStyle.Render("HIGHEST MOMENTUM (1H+1D+1W COMBINED)"))
-		sections = append(sections, "")
-
-		for i, market := range momentum {
-			score := market.GetMomentumScore()
-			line := fmt.Sprintf("%2d. %-50s Score: %5.1f",
-				i+1,
-				truncate(market.Question, 48),
-				score*100)
-			sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("#A78BFA")).Render(line))
+		segment := string(runes[start:end])
+		if marked {
+			b.WriteString(match.Render(segment))
+		} else {
+			b.WriteString(base.Render(segment))
 		}
+		start = end
 	}
+	b.WriteString(base.Render(" "))
+	return b.String()
+}
 
-	engaged := getMostEngaged(m.markets, 10)
-	if len(engaged) > 0 {
-		sections = append(sections, "", "", titleStyle.Render("MOST ENGAGED (VOLUME + COMMENTS)"))
-		sections = append(sections, "")
-
-		for i, market := range engaged {
-			line := fmt.Sprintf("%2d. %-50s Vol: %6s  Comments: %d",
-				i+1,
-				truncate(market.Question, 48),
-				formatCurrency(market.Volume24hr),
-				market.CommentCount)
-			sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("#FCD34D")).Render(line))
+func (m Model) renderHelp() string {
+	var helps []string
+	if m.searchMode {
+		helps = []string{
+			"type to search",
+			"enter/esc: exit search",
+			"backspace: delete",
+			"ctrl+u: clear",
 		}
-	}
-
-	tightSpreads := getTightestSpreads(m.markets, 10)
-	if len(tightSpreads) > 0 {
-		sections = append(sections, "", "", titleStyle.Render("TIGHTEST SPREADS (BEST LIQUIDITY)"))
-		sections = append(sections, "")
-
-		for i, market := range tightSpreads {
-			spread := market.GetSpread()
-			if spread > 0 {
-				line := fmt.Sprintf("%2d. %-50s Spread: %.4f",
-					i+1,
-					truncate(market.Question, 48),
-					spread)
-				sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("#34D399")).Render(line))
+	} else {
+		var items [][2]string
+		switch {
+		case m.currentView == viewDetail:
+			items = [][2]string{
+				{"detail.up", ""}, {"detail.down", "scroll"},
+				{"detail.search", "search"},
+				{"detail.next", ""}, {"detail.prev", "next/prev match"},
+				{"market.browser", "open in browser"},
+				{"back", "back"},
+				{"quit", "quit"},
+			}
+		case m.currentView != viewList:
+			items = [][2]string{{"back", "back"}, {"quit", "quit"}}
+		case m.currentPage == pageMarkets:
+			items = [][2]string{
+				{"nav.up", ""}, {"nav.down", "nav"},
+				{"detail.open", "details"},
+				{"search.start", "search"},
+				{"filter.cycle", "filter"},
+				{"sort.cycle", "sort"},
+				{"clear", "clear"},
+				{"select.toggle", "select"},
+				{"select.visual", "visual"},
+				{"watch.toggle", "watch"},
+				{"copy.ids", "copy ids"},
+				{"compare", "compare"},
+				{"layout.split", "split"},
+				{"quit", "quit"},
+			}
+		case m.currentPage == pageCategories:
+			items = [][2]string{
+				{"nav.up", ""}, {"nav.down", "nav"},
+				{"detail.open", "show markets"},
+				{"category.sort", "sort"},
+				{"category.view", "table/treemap"},
+				{"page.next", "switch page"},
+				{"refresh", "refresh"},
+				{"quit", "quit"},
+			}
+		case m.currentPage == pageArbitrage:
+			items = [][2]string{
+				{"nav.up", ""}, {"nav.down", "nav"},
+				{"detail.open", "open leg"},
+				{"arb.all", "all/opportunities"},
+				{"page.next", "switch page"},
+				{"refresh", "refresh"},
+				{"quit", "quit"},
+			}
+		case m.currentPage == pageResolved:
+			items = [][2]string{
+				{"nav.up", ""}, {"nav.down", "nav"},
+				{"detail.open", "details"},
+				{"resolved.search", "search"},
+				{"resolved.range", "date range"},
+				{"page.next", "switch page"},
+				{"refresh", "refresh"},
+				{"quit", "quit"},
+			}
+		default:
+			items = [][2]string{
+				{"nav.up", ""}, {"nav.down", "scroll"},
+				{"panel.prev", ""}, {"panel.next", "panel"},
+				{"panel.window", "window"},
+				{"detail.open", "details"},
+				{"page.next", "switch page"},
+				{"refresh", "refresh"},
+				{"quit", "quit"},
 			}
 		}
-	}
-
-	openInterest := getHighestOpenInterest(m.markets, 10)
-	if len(openInterest) > 0 {
-		sections = append(sections, "", "", titleStyle.Render("HIGHEST OPEN INTEREST (MONEY AT STAKE)"))
-		sections = append(sections, "")
+		if m.replaying() {
+			items = append(items,
+				[2]string{"replay.toggle", "play/pause"},
+				[2]string{"replay.slower", ""}, [2]string{"replay.faster", "speed"},
+				[2]string{"replay.prev", ""}, [2]string{"replay.next", "frame"},
+				[2]string{"replay.back", ""}, [2]string{"replay.forward", "seek"},
+			)
+		}
+		items = append(items, [2]string{"palette", "commands"}, [2]string{"help", "keys"})
 
-		for i, market := range openInterest {
-			if market.OpenInterest > 0 {
-				line := fmt.Sprintf("%2d. %-50s %8s",
-					i+1,
-					truncate(market.Question, 48),
-					formatCurrency(market.OpenInterest))
-				sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("#F472B6")).Render(line))
+		pending := ""
+		for _, item := range items {
+			b := m.keys.binding(item[0])
+			if !b.Enabled() {
+				continue
 			}
+			if item[1] == "" {
+				pending += b.Help().Key + " "
+				continue
+			}
+			helps = append(helps, pending+b.Help().Key+": "+item[1])
+			pending = ""
 		}
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, sections...)
-}
-
-func getTopMarketsByVolume(markets []types.Market, limit int) []types.Market {
-	sorted := make([]types.Market, len(markets))
-	copy(sorted, markets)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].GetVolume() > sorted[j].GetVolume()
-	})
-
-	if len(sorted) > limit {
-		sorted = sorted[:limit]
+	help := HelpStyle.Render(strings.Join(helps, " | "))
+	if m.status == "" {
+		return help
 	}
-	return sorted
-}
-
-func getTopMarketsByVolume24h(markets []types.Market, limit int) []types.Market {
-	sorted := make([]types.Market, len(markets))
-	copy(sorted, markets)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].Volume24hr > sorted[j].Volume24hr
-	})
-
-	if len(sorted) > limit {
-		sorted = sorted[:limit]
+	statusStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true)
+	if m.statusErr {
+		statusStyle = ErrorStyle
 	}
-	return sorted
+	return lipgloss.JoinVertical(lipgloss.Left, help, statusStyle.Render(m.status))
 }
 
-func getTopMovers(markets []types.Market, limit int) []types.Market {
-	sorted := make([]types.Market, len(markets))
-	copy(sorted, markets)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		absI := sorted[i].OneDayPriceChange
-		absJ := sorted[j].OneDayPriceChange
-		if absI < 0 {
-			absI = -absI
+func (m Model) renderKeyHelp() string {
+	sections := []struct {
+		title string
+		scope actionScope
+	}{
+		{"GLOBAL", scopeGlobal},
+		{"ALL PAGES", scopeList},
+		{"MARKETS PAGE", scopeMarkets},
+		{"ANALYTICS PAGE", scopeAnalytics},
+		{"CATEGORIES PAGE", scopeCategories},
+		{"ARBITRAGE PAGE", scopeArbitrage},
+		{"RESOLVED PAGE", scopeResolved},
+		{"DETAIL VIEW", scopeDetail},
+		{"MARKETS & DETAIL", scopeMarket},
+		{"REPLAY", scopeReplay},
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(colorSecondary).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Width(22)
+	descStyle := lipgloss.NewStyle().Foreground(colorSubtle)
+
+	var columns []string
+	for _, section := range sections {
+		if section.scope == scopeReplay && !m.replaying() {
+			continue
 		}
-		if absJ < 0 {
-			absJ = -absJ
+		lines := []string{titleStyle.Render(section.title), ""}
+		for i := range actions {
+			a := &actions[i]
+			b := m.keys.binding(a.id)
+			if a.scope != section.scope || !b.Enabled() {
+				continue
+			}
+			lines = append(lines, keyStyle.Render(b.Help().Key)+descStyle.Render(b.Help().Desc)+MutedStyle.Render(" "+a.id))
 		}
-		return absI > absJ
-	})
-
-	if len(sorted) > limit {
-		sorted = sorted[:limit]
-	}
-	return sorted
-}
-
-func getTopMomentum(markets []types.Market, limit int) []types.Market {
-	sorted := make([]types.Market, len(markets))
-	copy(sorted, markets)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].GetMomentumScore() > sorted[j].GetMomentumScore()
-	})
-
-	if len(sorted) > limit {
-		sorted = sorted[:limit]
-	}
-	return sorted
-}
-
-func getMostEngaged(markets []types.Market, limit int) []types.Market {
-	sorted := make([]types.Market, len(markets))
-	copy(sorted, markets)
-
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].GetEngagementScore() > sorted[j].GetEngagementScore()
-	})
-
-	if len(sorted) > limit {
-		sorted = sorted[:limit]
-	}
-	return sorted
-}
-
-func getTightestSpreads(markets []types.Market, limit int) []types.Market {
-	filtered := make([]types.Market, 0)
-	for _, m := range markets {
-		if m.GetSpread() > 0 {
-			filtered = append(filtered, m)
+		if len(lines) == 2 {
+			continue
 		}
+		columns = append(columns, lipgloss.NewStyle().MarginRight(4).Render(strings.Join(lines, "\n")))
 	}
 
-	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].GetSpread() < filtered[j].GetSpread()
-	})
-
-	if len(filtered) > limit {
-		filtered = filtered[:limit]
-	}
-	return filtered
-}
-
-func getHighestOpenInterest(markets []types.Market, limit int) []types.Market {
-	filtered := make([]types.Market, 0)
-	for _, m := range markets {
-		if m.OpenInterest > 0 {
-			filtered = append(filtered, m)
+	var rows []string
+	var row []string
+	for _, column := range columns {
+		if len(row) > 0 && lipgloss.Width(lipgloss.JoinHorizontal(lipgloss.Top, append(row, column)...)) > m.width-4 {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...), "")
+			row = nil
 		}
+		row = append(row, column)
 	}
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	body := lipgloss.JoinVertical(lipgloss.Left, rows...)
 
-	sort.Slice(filtered, func(i, j int) bool {
-		return filtered[i].OpenInterest > filtered[j].OpenInterest
-	})
-
-	if len(filtered) > limit {
-		filtered = filtered[:limit]
-	}
-	return filtered
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		"",
+		BrandStyle.Render("POLYTERM")+" "+HeaderStyle.Render(fmt.Sprintf("Keybindings (preset: %s)", m.keys.preset)),
+		"",
+		body,
+		HelpStyle.Render(fmt.Sprintf("Remap keys in %s | %s: close", config.Path(), m.keys.binding("help").Help().Key)),
+	)
 }
 
 func (m Model) renderMarketDetail() string {
-	displayMarkets := m.filteredMarkets
-	if len(displayMarkets) == 0 {
-		displayMarkets = m.markets
-	}
-
-	if m.selectedMarket >= len(displayMarkets) {
-		return MutedStyle.Render("Market not found")
+	top, bottom := m.detailSections()
+	sections := top
+	if m.selected.Description != "" {
+		sections = append(sections, m.renderDescription(), "")
 	}
+	sections = append(sections, bottom...)
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
 
-	market := displayMarkets[m.selectedMarket]
+func (m Model) detailSections() (top, bottom []string) {
+	market := m.selected
 	yesOdds, noOdds := api.ParseOdds(&market)
 
 	var sections []string
 
 	sections = append(sections, "", "")
 
-	header := BrandStyle.Render("POLYTERM") + " " + HeaderStyle.Render(fmt.Sprintf("Market #%d Details", m.selectedMarket+1))
+	header := BrandStyle.Render("POLYTERM") + " " + HeaderStyle.Render("Market Details")
+	if i := marketIndex(m.displayMarkets(), market.ID); i >= 0 {
+		header = BrandStyle.Render("POLYTERM") + " " + HeaderStyle.Render(fmt.Sprintf("Market #%d Details", i+1))
+	} else if marketIndex(m.markets, market.ID) < 0 {
+		header += "  " + MutedStyle.Render("(not in the current market list)")
+	}
 	sections = append(sections, header)
 	sections = append(sections, "")
 
 	questionStyle := lipgloss.NewStyle().
-		Foreground(polyLight).
+		Foreground(colorSubtle).
 		Bold(true).
 		Width(m.width - 6)
 	sections = append(sections, questionStyle.Render(market.Question))
@@ -652,12 +725,12 @@ func (m Model) renderMarketDetail() string {
 
 	volumeBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(polyPink).
+		BorderForeground(colorAccent).
 		Padding(1, 2).
 		Width(35).
 		Render(lipgloss.JoinVertical(
 			lipgloss.Left,
-			lipgloss.NewStyle().Foreground(polyPink).Bold(true).Render("VOLUME & LIQUIDITY"),
+			lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Render("VOLUME & LIQUIDITY"),
 			"",
 			StatsLabelStyle.Render("24h Volume:")+lipgloss.NewStyle().Render("  ")+VolumeStyle.Render(formatCurrency(market.Volume24hr)),
 			StatsLabelStyle.Render("Total Volume:")+lipgloss.NewStyle().Render(" ")+StatsValueStyle.Render(formatCurrency(market.GetVolume())),
@@ -666,37 +739,47 @@ func (m Model) renderMarketDetail() string {
 
 	priceBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(polyBlue).
+		BorderForeground(colorPrimary).
 		Padding(1, 2).
 		Width(35).
 		Render(lipgloss.JoinVertical(
 			lipgloss.Left,
-			lipgloss.NewStyle().Foreground(polyBlue).Bold(true).Render("PRICE DATA"),
+			lipgloss.NewStyle().Foreground(colorPrimary).Bold(true).Render("PRICE DATA"),
 			"",
 			StatsLabelStyle.Render("Last Price:")+lipgloss.NewStyle().Render("   ")+StatsValueStyle.Render(fmt.Sprintf("$%.3f", market.LastTradePrice)),
 			StatsLabelStyle.Render("24h Change:")+lipgloss.NewStyle().Render("   ")+getPriceChangeStyle(market.OneDayPriceChange).Render(fmt.Sprintf("%+.2f%%", market.OneDayPriceChange*100)),
 			StatsLabelStyle.Render("Status:")+lipgloss.NewStyle().Render("        ")+getActiveStyle(market.Active).Render(getStatusText(market.Active, market.Closed)),
 		))
 
-	statsRow := lipgloss.JoinHorizontal(lipgloss.Top, volumeBox, "  ", priceBox)
-	sections = append(sections, statsRow)
-	sections = append(sections, "")
+	signals := m.signals[market.ID]
+	zScore := "n/a"
+	if signals.Samples >= metrics.MinSamples {
+		zScore = fmt.Sprintf("%+.1fσ (%d)", signals.MoveZScore, signals.Samples)
+	}
+	activityColor := colorSecondary
+	if signals.Unusual() {
+		activityColor = colorWarning
+	}
+	activityBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(activityColor).
+		Padding(1, 2).
+		Width(35).
+		Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.NewStyle().Foreground(activityColor).Bold(true).Render("ACTIVITY"),
+			"",
+			StatsLabelStyle.Render("Volume pace:")+"  "+StatsValueStyle.Render(fmt.Sprintf("%.2fx / %.2fx", signals.VolumeAccel24h, signals.VolumeAccel1w)),
+			StatsLabelStyle.Render("Move z-score:")+" "+StatsValueStyle.Render(zScore),
+			StatsLabelStyle.Render("Liq-adj move:")+" "+StatsValueStyle.Render(fmt.Sprintf("%.1f", signals.AdjustedMove)),
+		))
 
-	if market.Description != "" {
-		descBox := lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(polyPurple).
-			Padding(1, 2).
-			Width(m.width - 8).
-			Render(lipgloss.JoinVertical(
-				lipgloss.Left,
-				lipgloss.NewStyle().Foreground(polyPurple).Bold(true).Render("MARKET DETAILS"),
-				"",
-				lipgloss.NewStyle().Foreground(polyLight).Render(truncateDesc(market.Description, m.width-16)),
-			))
-		sections = append(sections, descBox)
-		sections = append(sections, "")
+	statsRow := lipgloss.JoinHorizontal(lipgloss.Top, volumeBox, "  ", priceBox, "  ", activityBox)
+	if signals.Unusual() {
+		statsRow = lipgloss.JoinVertical(lipgloss.Left, statsRow, lipgloss.NewStyle().Foreground(colorWarning).Bold(true).Render("  Unusual activity: "+strings.Join(signals.Reasons, ", ")))
 	}
+	sections = append(sections, statsRow)
+	sections = append(sections, "")
 
 	metaBox := lipgloss.NewStyle().
 		Padding(0, 2).
@@ -706,13 +789,9 @@ func (m Model) renderMarketDetail() string {
 				getCategory(market.Category),
 				market.ID,
 				formatEndDate(market.EndDate))),
+			MutedStyle.Render("Polymarket: ")+hyperlink(api.MarketURL(&market), lipgloss.NewStyle().Foreground(colorPrimary).Underline(true).Render(api.MarketURL(&market))),
 		))
-	sections = append(sections, metaBox)
-
-	help := m.renderHelp()
-	sections = append(sections, "", help)
-
-	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return sections, []string{metaBox, "", m.renderHelp()}
 }
 
 func renderProbabilityBar(yesOdds, noOdds float64, width int) string {
@@ -726,18 +805,21 @@ func renderProbabilityBar(yesOdds, noOdds float64, width int) string {
 
 	yesBar := strings.Repeat("█", yesBlocks)
 	noBar := strings.Repeat("█", noBlocks)
+	if colorless() {
+		noBar = strings.Repeat("░", noBlocks)
+	}
 
-	yesStyle := lipgloss.NewStyle().Foreground(greenYes)
-	noStyle := lipgloss.NewStyle().Foreground(redNo)
+	yesStyle := lipgloss.NewStyle().Foreground(colorPositive)
+	noStyle := lipgloss.NewStyle().Foreground(colorNegative)
 
 	bar := yesStyle.Render(yesBar) + noStyle.Render(noBar)
 
 	labelStyle := lipgloss.NewStyle().Bold(true)
 	labels := lipgloss.JoinHorizontal(
 		lipgloss.Top,
-		labelStyle.Foreground(greenYes).Render(fmt.Sprintf("YES %.1f%%", yesOdds)),
+		labelStyle.Foreground(colorPositive).Render(fmt.Sprintf("YES %.1f%%", yesOdds)),
 		strings.Repeat(" ", barWidth-18),
-		labelStyle.Foreground(redNo).Render(fmt.Sprintf("NO %.1f%%", noOdds)),
+		labelStyle.Foreground(colorNegative).Render(fmt.Sprintf("NO %.1f%%", noOdds)),
 	)
 
 	return lipgloss.JoinVertical(lipgloss.Left, labels, bar)
@@ -746,37 +828,37 @@ func renderProbabilityBar(yesOdds, noOdds float64, width int) string {
 func renderOddsBoxes(yesOdds, noOdds, change float64) string {
 	yesBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(greenYes).
+		BorderForeground(colorPositive).
 		Padding(2, 4).
 		Width(25).
 		Align(lipgloss.Center).
 		Render(lipgloss.JoinVertical(
 			lipgloss.Center,
-			lipgloss.NewStyle().Foreground(greenYes).Bold(true).Render("YES"),
-			lipgloss.NewStyle().Foreground(greenYes).Bold(true).Render(fmt.Sprintf("%.1f%%", yesOdds)),
+			lipgloss.NewStyle().Foreground(colorPositive).Bold(true).Render("YES"),
+			lipgloss.NewStyle().Foreground(colorPositive).Bold(true).Render(fmt.Sprintf("%.1f%%", yesOdds)),
 		))
 
 	noBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(redNo).
+		BorderForeground(colorNegative).
 		Padding(2, 4).
 		Width(25).
 		Align(lipgloss.Center).
 		Render(lipgloss.JoinVertical(
 			lipgloss.Center,
-			lipgloss.NewStyle().Foreground(redNo).Bold(true).Render("NO"),
-			lipgloss.NewStyle().Foreground(redNo).Bold(true).Render(fmt.Sprintf("%.1f%%", noOdds)),
+			lipgloss.NewStyle().Foreground(colorNegative).Bold(true).Render("NO"),
+			lipgloss.NewStyle().Foreground(colorNegative).Bold(true).Render(fmt.Sprintf("%.1f%%", noOdds)),
 		))
 
 	changeBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(polyPurple).
+		BorderForeground(colorSecondary).
 		Padding(2, 4).
 		Width(25).
 		Align(lipgloss.Center).
 		Render(lipgloss.JoinVertical(
 			lipgloss.Center,
-			lipgloss.NewStyle().Foreground(polyPurple).Bold(true).Render("24H CHANGE"),
+			lipgloss.NewStyle().Foreground(colorSecondary).Bold(true).Render("24H CHANGE"),
 			getPriceChangeStyle(change).Render(fmt.Sprintf("%+.2f%%", change*100)),
 		))
 
@@ -800,28 +882,20 @@ func getCategory(cat string) string {
 	return cat
 }
 
-func truncateDesc(desc string, maxWidth int) string {
-	maxLen := maxWidth * 6
-	if len(desc) > maxLen {
-		return desc[:maxLen] + "..."
-	}
-	return desc
-}
-
 func getPriceChangeStyle(change float64) lipgloss.Style {
 	if change > 0 {
-		return lipgloss.NewStyle().Foreground(greenYes).Bold(true)
+		return lipgloss.NewStyle().Foreground(colorPositive).Bold(true)
 	} else if change < 0 {
-		return lipgloss.NewStyle().Foreground(redNo).Bold(true)
+		return lipgloss.NewStyle().Foreground(colorNegative).Bold(true)
 	}
-	return lipgloss.NewStyle().Foreground(grayMuted)
+	return lipgloss.NewStyle().Foreground(colorMuted)
 }
 
 func getActiveStyle(active bool) lipgloss.Style {
 	if active {
-		return lipgloss.NewStyle().Foreground(greenYes).Bold(true)
+		return lipgloss.NewStyle().Foreground(colorPositive).Bold(true)
 	}
-	return lipgloss.NewStyle().Foreground(grayMuted)
+	return lipgloss.NewStyle().Foreground(colorMuted)
 }
 
 func formatEndDate(dateStr string) string {
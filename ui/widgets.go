@@ -0,0 +1,335 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"polyterm/api"
+	"polyterm/config"
+	"polyterm/metrics"
+	"polyterm/query"
+	"polyterm/types"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type widgetInput struct {
+	markets []types.Market
+	signals map[string]metrics.Signals
+	window  api.Window
+	metric  query.Metric
+	listed  map[string]time.Time
+
+	watchlist []string
+}
+
+type rankFunc func(in widgetInput, limit int) []types.Market
+
+type valueFunc func(m *types.Market, in widgetInput) (string, lipgloss.Style)
+
+type widgetType struct {
+	title   string
+	windows []api.Window
+	window  api.Window
+	metric  string
+	rank    rankFunc
+	value   valueFunc
+}
+
+var widgetTypes = map[string]widgetType{
+	"volume": {
+		title: "TOP BY TOTAL VOLUME",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return api.TopByVolume(in.markets, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			yesOdds, _ := api.ParseOdds(m)
+			return fmt.Sprintf("%8s  YES %5.1f%%", formatCurrency(m.GetVolume()), yesOdds), lipgloss.NewStyle().Foreground(colorText)
+		},
+	},
+	"window-volume": {
+		title:   "TOP BY VOLUME",
+		windows: []api.Window{api.Window24h, api.Window1w, api.Window1mo},
+		window:  api.Window24h,
+		rank: func(in widgetInput, limit int) []types.Market {
+			return api.TopByWindowVolume(in.markets, in.window, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			yesOdds, _ := api.ParseOdds(m)
+			return fmt.Sprintf("%8s  YES %5.1f%%", formatCurrency(api.WindowVolume(m, in.window)), yesOdds), lipgloss.NewStyle().Foreground(colorText)
+		},
+	},
+	"movers": {
+		title:   "BIGGEST PRICE MOVERS",
+		windows: []api.Window{api.Window1h, api.Window24h, api.Window1w, api.Window1mo},
+		window:  api.Window24h,
+		rank: func(in widgetInput, limit int) []types.Market {
+			return api.TopMovers(in.markets, in.window, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			change := api.PriceChange(m, in.window)
+			return fmt.Sprintf("%+6.2f%%", change*100), getPriceChangeStyle(change)
+		},
+	},
+	"momentum": {
+		title: "HIGHEST MOMENTUM (1H+1D+1W)",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return api.TopMomentum(in.markets, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			return fmt.Sprintf("Score %5.1f", m.GetMomentumScore()*100), lipgloss.NewStyle().Foreground(colorSecondary)
+		},
+	},
+	"engaged": {
+		title: "MOST ENGAGED (VOLUME + COMMENTS)",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return api.MostEngaged(in.markets, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			return fmt.Sprintf("%7s  %4d cmts", formatCurrency(m.Volume24hr), m.CommentCount), lipgloss.NewStyle().Foreground(colorWarning)
+		},
+	},
+	"spreads": {
+		title: "TIGHTEST SPREADS",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return api.TightestSpreads(in.markets, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			return fmt.Sprintf("%.4f", m.GetSpread()), lipgloss.NewStyle().Foreground(colorPositive)
+		},
+	},
+	"open-interest": {
+		title: "HIGHEST OPEN INTEREST",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return api.HighestOpenInterest(in.markets, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			return formatCurrency(m.OpenInterest), lipgloss.NewStyle().Foreground(colorAccent)
+		},
+	},
+	"closing-soon": {
+		title: "CLOSING SOON",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return api.ClosingSoon(in.markets, time.Now(), limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			end, _ := time.Parse(time.RFC3339, m.EndDate)
+			return formatRemaining(time.Until(end)), lipgloss.NewStyle().Foreground(colorWarning)
+		},
+	},
+	"unusual": {
+		title: "UNUSUAL ACTIVITY",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return getUnusualActivity(in.markets, in.signals, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			return in.signals[m.ID].Reasons[0], lipgloss.NewStyle().Foreground(colorWarning)
+		},
+	},
+	"just-listed": {
+		title: "JUST LISTED",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return getJustListed(in.markets, in.listed, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			yesOdds, _ := api.ParseOdds(m)
+			return fmt.Sprintf("%6s ago  YES %5.1f%%", formatRemaining(time.Since(in.listed[m.ID])), yesOdds), lipgloss.NewStyle().Foreground(colorPositive)
+		},
+	},
+	"watchlist": {
+		title: "WATCHLIST",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return getWatchlist(in.markets, in.watchlist, limit)
+		},
+		value: func(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+			yesOdds, _ := api.ParseOdds(m)
+			change := m.OneDayPriceChange * 100
+			style := lipgloss.NewStyle().Foreground(colorPositive)
+			if change < 0 {
+				style = lipgloss.NewStyle().Foreground(colorNegative)
+			}
+			return fmt.Sprintf("YES %5.1f%%  %+5.1f%%", yesOdds, change), style
+		},
+	},
+	"top": {
+		title:  "TOP BY",
+		metric: "volume24h",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return getTopByMetric(in.markets, in.metric, false, limit)
+		},
+		value: metricValue,
+	},
+	"bottom": {
+		title:  "LOWEST BY",
+		metric: "spread",
+		rank: func(in widgetInput, limit int) []types.Market {
+			return getTopByMetric(in.markets, in.metric, true, limit)
+		},
+		value: metricValue,
+	},
+}
+
+var defaultWidgets = []config.Widget{
+	{Type: "volume"},
+	{Type: "window-volume"},
+	{Type: "movers"},
+	{Type: "momentum"},
+	{Type: "engaged"},
+	{Type: "spreads"},
+	{Type: "open-interest"},
+	{Type: "unusual"},
+	{Type: "just-listed"},
+}
+
+func widgetTypeNames() []string {
+	names := make([]string, 0, len(widgetTypes))
+	for name := range widgetTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newPanel(w config.Widget) (analyticsPanel, error) {
+	wt, ok := widgetTypes[w.Type]
+	if !ok {
+		return analyticsPanel{}, fmt.Errorf("unknown widget %q (available: %s)", w.Type, strings.Join(widgetTypeNames(), ", "))
+	}
+
+	p := analyticsPanel{
+		title:   wt.title,
+		windows: wt.windows,
+		limit:   panelRankLimit,
+		width:   max(1, w.Width),
+	}
+	if w.Limit > 0 {
+		p.limit = w.Limit
+	}
+
+	var metric query.Metric
+	switch {
+	case wt.metric == "" && w.Metric != "":
+		return analyticsPanel{}, fmt.Errorf("widget %q does not take a metric", w.Type)
+	case wt.metric != "":
+		name := w.Metric
+		if name == "" {
+			name = wt.metric
+		}
+		metric, ok = query.Lookup(name)
+		if !ok {
+			return analyticsPanel{}, fmt.Errorf("widget %q: unknown metric %q (available: %s)", w.Type, name, strings.Join(query.Names(), ", "))
+		}
+		p.title += " " + strings.ToUpper(metric.Label)
+	}
+
+	window := wt.window
+	if w.Window != "" {
+		window = -1
+		for i, name := range api.WindowNames {
+			if name == w.Window {
+				window = api.Window(i)
+			}
+		}
+		if window < 0 {
+			return analyticsPanel{}, fmt.Errorf("widget %q: unknown window %q (available: %s)", w.Type, w.Window, strings.Join(api.WindowNames, ", "))
+		}
+	}
+	for i, win := range wt.windows {
+		if win == window {
+			p.window = i
+		}
+	}
+	if w.Window != "" && (len(wt.windows) == 0 || wt.windows[p.window] != window) {
+		return analyticsPanel{}, fmt.Errorf("widget %q does not support window %q", w.Type, w.Window)
+	}
+
+	if w.Filter != "" {
+		expr, err := query.Parse(w.Filter)
+		if err != nil {
+			return analyticsPanel{}, fmt.Errorf("widget %q: %w", w.Type, err)
+		}
+		p.filter = expr
+	}
+	if w.Title != "" {
+		p.title = strings.ToUpper(w.Title)
+	}
+
+	p.metric = metric
+	p.rank = wt.rank
+	p.value = wt.value
+	return p, nil
+}
+
+func newDashboard(d config.Dashboard) ([]analyticsPanel, []panelRow, error) {
+	if len(d.Rows) == 0 {
+		panels := make([]analyticsPanel, 0, len(defaultWidgets))
+		for _, w := range defaultWidgets {
+			p, err := newPanel(w)
+			if err != nil {
+				return nil, nil, err
+			}
+			panels = append(panels, p)
+		}
+		return panels, nil, nil
+	}
+
+	var panels []analyticsPanel
+	var rows []panelRow
+	var errs []error
+	for i, row := range d.Rows {
+		if len(row.Widgets) == 0 {
+			errs = append(errs, fmt.Errorf("dashboard: row %d has no widgets", i+1))
+			continue
+		}
+		if row.Height != 0 && row.Height < panelMinHeight {
+			errs = append(errs, fmt.Errorf("dashboard: row %d height must be at least %d", i+1, panelMinHeight))
+		}
+		r := panelRow{height: row.Height}
+		for j, w := range row.Widgets {
+			p, err := newPanel(w)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("dashboard: row %d widget %d: %w", i+1, j+1, err))
+				continue
+			}
+			r.panels = append(r.panels, len(panels))
+			panels = append(panels, p)
+		}
+		if len(r.panels) > 0 {
+			rows = append(rows, r)
+		}
+	}
+	return panels, rows, errors.Join(errs...)
+}
+
+func metricValue(m *types.Market, in widgetInput) (string, lipgloss.Style) {
+	metric := in.metric
+	v := metric.Value(m)
+	style := lipgloss.NewStyle().Foreground(colorPrimary)
+	switch metric.Kind {
+	case query.KindCurrency:
+		return formatCurrency(v), style
+	case query.KindPercent:
+		if strings.HasPrefix(metric.Name, "change") {
+			return fmt.Sprintf("%+6.2f%%", v), getPriceChangeStyle(v)
+		}
+		return fmt.Sprintf("%5.1f%%", v), style
+	case query.KindPrice:
+		return fmt.Sprintf("%.4f", v), style
+	}
+	return fmt.Sprintf("%.1f", v), style
+}
+
+func formatRemaining(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "closed"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}